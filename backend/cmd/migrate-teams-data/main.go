@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/sticktoss/backend/internal/db"
+	"github.com/sticktoss/backend/internal/gamedata"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Rewrites the teams data of every game and game revision stored in an older schema version
+// in the current one. Older data can still be read without this, since it is upgraded when
+// decoded; migrating saves doing that on every read and lets old upgrades be retired.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report how many rows would be rewritten without saving them")
+	flag.Parse()
+
+	database, err := db.New(db.GetConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	database = database.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Warn)})
+
+	// Leave schema migrations to the server, so a dry run doesn't change the database
+	if err := checkSchema(database); err != nil {
+		log.Fatalf("%v; start the server once to migrate the database, then run this again", err)
+	}
+
+	games, skippedGames, err := migrateGames(database, *dryRun)
+	if err != nil {
+		log.Fatalf("Failed to migrate games: %v", err)
+	}
+	revisions, skippedRevisions, err := migrateRevisions(database, *dryRun)
+	if err != nil {
+		log.Fatalf("Failed to migrate game revisions: %v", err)
+	}
+
+	verb := "Rewrote"
+	if *dryRun {
+		verb = "Would rewrite"
+	}
+	log.Printf("%s teams data of %d games and %d game revisions to version %d", verb, games, revisions, gamedata.CurrentVersion)
+	if skippedGames > 0 || skippedRevisions > 0 {
+		log.Printf("Skipped unreadable teams data of %d games and %d game revisions", skippedGames, skippedRevisions)
+	}
+}
+
+// checkSchema makes sure the tables this tool rewrites have been migrated
+func checkSchema(database *gorm.DB) error {
+	for _, table := range []string{"games", "game_revisions"} {
+		if !database.Migrator().HasColumn(table, "teams_data") {
+			return fmt.Errorf("database schema is out of date: %s has no teams_data column", table)
+		}
+	}
+	return nil
+}
+
+// migrateGames upgrades the teams data of games, returning how many needed it and how many
+// couldn't be read
+func migrateGames(database *gorm.DB, dryRun bool) (int, int, error) {
+	count, skipped := 0, 0
+	var games []models.Game
+	err := database.Select("share_id", "teams_data").FindInBatches(&games, 100, func(_ *gorm.DB, _ int) error {
+		for _, game := range games {
+			data, changed, err := gamedata.Upgrade(game.TeamsData)
+			if err != nil {
+				log.Printf("Skipping teams data of game %s: %v", game.ShareID, err)
+				skipped++
+				continue
+			}
+			if !changed {
+				continue
+			}
+			count++
+			if dryRun {
+				continue
+			}
+			if err := database.Model(&models.Game{}).Where("share_id = ?", game.ShareID).
+				Update("teams_data", data).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	return count, skipped, err
+}
+
+// migrateRevisions upgrades the teams data of game revisions, returning how many needed it
+// and how many couldn't be read
+func migrateRevisions(database *gorm.DB, dryRun bool) (int, int, error) {
+	count, skipped := 0, 0
+	var revisions []models.GameRevision
+	err := database.Select("id", "game_share_id", "number", "teams_data").FindInBatches(&revisions, 100, func(_ *gorm.DB, _ int) error {
+		for _, r := range revisions {
+			data, changed, err := gamedata.Upgrade(r.TeamsData)
+			if err != nil {
+				log.Printf("Skipping teams data of game %s revision %d: %v", r.GameShareID, r.Number, err)
+				skipped++
+				continue
+			}
+			if !changed {
+				continue
+			}
+			count++
+			if dryRun {
+				continue
+			}
+			if err := database.Model(&models.GameRevision{}).Where("id = ?", r.ID).
+				Update("teams_data", data).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	return count, skipped, err
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -29,6 +30,9 @@ func main() {
 
 	log.Println("Database connected and migrations completed")
 
+	// Keep creating sessions from recurring schedules as time passes
+	go api.MaterializeSchedules(database, time.Hour)
+
 	// Set Gin mode
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.DebugMode)
@@ -50,6 +54,15 @@ func main() {
 	authHandler := api.NewAuthHandler(database)
 	playerHandler := api.NewPlayerHandler(database)
 	groupHandler := api.NewGroupHandler(database)
+	gameHandler := api.NewGameHandler(database)
+	skillScaleHandler := api.NewSkillScaleHandler(database)
+	sessionHandler := api.NewSessionHandler(database)
+	scheduleHandler := api.NewScheduleHandler(database)
+	meHandler := api.NewMeHandler(database)
+	organizationHandler := api.NewOrganizationHandler(database)
+	seasonHandler := api.NewSeasonHandler(database)
+	tournamentHandler := api.NewTournamentHandler(database)
+	ledgerHandler := api.NewLedgerHandler(database)
 
 	// Public routes
 	r.POST("/api/auth/signup", authHandler.Signup)
@@ -71,6 +84,36 @@ func main() {
 		protected.POST("/players", playerHandler.CreatePlayer)
 		protected.PUT("/players/:id", playerHandler.UpdatePlayer)
 		protected.DELETE("/players/:id", playerHandler.DeletePlayer)
+		protected.GET("/players/:id/ratings", playerHandler.GetPlayerRatings)
+		protected.GET("/players/:id/weight-history", playerHandler.GetWeightHistory)
+		protected.POST("/players/:id/invite", playerHandler.CreateInvite)
+		protected.DELETE("/players/:id/claim", playerHandler.Unclaim)
+
+		// Self-service routes for users who have claimed a player
+		protected.POST("/invites/:token/accept", meHandler.AcceptInvite)
+		protected.GET("/me/players", meHandler.GetMyPlayers)
+		protected.PUT("/me/players/:id", meHandler.UpdateMyPlayer)
+		protected.GET("/me/players/:id/sessions", meHandler.GetMySessions)
+		protected.PUT("/me/players/:id/sessions/:session_id/rsvp", meHandler.SetMyRSVP)
+		protected.GET("/me/players/:id/games", meHandler.GetMyGames)
+
+		// Skill scale routes
+		protected.GET("/skill-scales", skillScaleHandler.GetSkillScales)
+		protected.POST("/skill-scales", skillScaleHandler.CreateSkillScale)
+		protected.PUT("/skill-scales/default", skillScaleHandler.SetDefaultSkillScale)
+		protected.PUT("/skill-scales/:id", skillScaleHandler.UpdateSkillScale)
+		protected.DELETE("/skill-scales/:id", skillScaleHandler.DeleteSkillScale)
+
+		// Organization routes
+		protected.GET("/organizations", organizationHandler.GetOrganizations)
+		protected.GET("/organizations/:id", organizationHandler.GetOrganization)
+		protected.POST("/organizations", organizationHandler.CreateOrganization)
+		protected.PUT("/organizations/:id", organizationHandler.UpdateOrganization)
+		protected.DELETE("/organizations/:id", organizationHandler.DeleteOrganization)
+		protected.GET("/organizations/:id/members", organizationHandler.GetOrganizationMembers)
+		protected.POST("/organizations/:id/members", organizationHandler.AddOrganizationMember)
+		protected.PUT("/organizations/:id/members/:user_id", organizationHandler.SetOrganizationMemberRole)
+		protected.DELETE("/organizations/:id/members/:user_id", organizationHandler.RemoveOrganizationMember)
 
 		// Group routes
 		protected.GET("/groups", groupHandler.GetGroups)
@@ -78,17 +121,105 @@ func main() {
 		protected.POST("/groups", groupHandler.CreateGroup)
 		protected.PUT("/groups/:id", groupHandler.UpdateGroup)
 		protected.DELETE("/groups/:id", groupHandler.DeleteGroup)
+		protected.PUT("/groups/:id/organization", groupHandler.MoveGroup)
+
+		// Group member routes
+		protected.GET("/groups/:id/members", groupHandler.GetMembers)
+		protected.PUT("/groups/:id/members/:user_id", groupHandler.SetMemberRole)
+		protected.DELETE("/groups/:id/members/:user_id", groupHandler.RemoveMember)
+		protected.GET("/groups/:id/invites", groupHandler.GetGroupInvites)
+		protected.POST("/groups/:id/invites", groupHandler.CreateGroupInvite)
+		protected.DELETE("/groups/:id/invites/:invite_id", groupHandler.DeleteGroupInvite)
+		protected.POST("/group-invites/:token/accept", groupHandler.AcceptGroupInvite)
+		protected.POST("/groups/:id/transfer", groupHandler.TransferOwnership)
 
 		// Group-Player routes
 		protected.POST("/groups/:id/players", groupHandler.AddPlayerToGroup)
 		protected.DELETE("/groups/:id/players/:player_id", groupHandler.RemovePlayerFromGroup)
+		protected.PUT("/groups/:id/players/:player_id", groupHandler.SetGroupSkillWeight)
+		protected.PUT("/groups/:id/players/:player_id/regular", groupHandler.SetRegular)
+		protected.PUT("/groups/:id/players/:player_id/spare", groupHandler.SetSpare)
+		protected.GET("/groups/:id/skill-scale", groupHandler.GetGroupSkillScale)
+		protected.PUT("/groups/:id/skill-scale", groupHandler.SetGroupSkillScale)
 
 		// Group logo routes
 		protected.POST("/groups/:id/logo", groupHandler.UploadGroupLogo)
 		protected.DELETE("/groups/:id/logo", groupHandler.DeleteGroupLogo)
 
+		// Session routes
+		protected.GET("/groups/:id/sessions", sessionHandler.GetSessions)
+		protected.GET("/groups/:id/sessions/:session_id", sessionHandler.GetSession)
+		protected.POST("/groups/:id/sessions", sessionHandler.CreateSession)
+		protected.PUT("/groups/:id/sessions/:session_id", sessionHandler.UpdateSession)
+		protected.DELETE("/groups/:id/sessions/:session_id", sessionHandler.DeleteSession)
+		protected.GET("/groups/:id/sessions/:session_id/attendance", sessionHandler.GetAttendance)
+		protected.PUT("/groups/:id/sessions/:session_id/attendance/:player_id", sessionHandler.SetRSVP)
+		protected.POST("/groups/:id/sessions/:session_id/attendance/:player_id/check-in", sessionHandler.CheckIn)
+		protected.DELETE("/groups/:id/sessions/:session_id/attendance/:player_id/check-in", sessionHandler.UndoCheckIn)
+		protected.GET("/groups/:id/sessions/:session_id/events", sessionHandler.GetSessionEvents)
+		protected.GET("/groups/:id/sessions/:session_id/replacements", sessionHandler.GetReplacements)
+		protected.POST("/groups/:id/sessions/:session_id/replacements", sessionHandler.InviteReplacement)
+		protected.POST("/groups/:id/sessions/:session_id/charges", sessionHandler.ChargeSession)
+		protected.GET("/groups/:id/reliability", sessionHandler.GetReliability)
+
+		// Ledger routes
+		protected.GET("/groups/:id/ledger", ledgerHandler.GetLedger)
+		protected.POST("/groups/:id/ledger/payments", ledgerHandler.RecordPayment)
+		protected.DELETE("/groups/:id/ledger/:entry_id", ledgerHandler.DeleteLedgerEntry)
+		protected.GET("/groups/:id/balances", ledgerHandler.GetBalances)
+
+		// Recurring schedule routes
+		protected.GET("/groups/:id/schedules", scheduleHandler.GetSchedules)
+		protected.GET("/groups/:id/schedules/:schedule_id", scheduleHandler.GetSchedule)
+		protected.POST("/groups/:id/schedules", scheduleHandler.CreateSchedule)
+		protected.PUT("/groups/:id/schedules/:schedule_id", scheduleHandler.UpdateSchedule)
+		protected.DELETE("/groups/:id/schedules/:schedule_id", scheduleHandler.DeleteSchedule)
+		protected.GET("/groups/:id/schedules/:schedule_id/occurrences", scheduleHandler.GetOccurrences)
+		protected.POST("/groups/:id/schedules/:schedule_id/exceptions", scheduleHandler.AddScheduleException)
+		protected.DELETE("/groups/:id/schedules/:schedule_id/exceptions/:date", scheduleHandler.DeleteScheduleException)
+
+		// Season routes
+		protected.GET("/groups/:id/seasons", seasonHandler.GetSeasons)
+		protected.GET("/groups/:id/seasons/:season_id", seasonHandler.GetSeason)
+		protected.POST("/groups/:id/seasons", seasonHandler.CreateSeason)
+		protected.DELETE("/groups/:id/seasons/:season_id", seasonHandler.DeleteSeason)
+		protected.POST("/groups/:id/seasons/:season_id/schedule", seasonHandler.ScheduleSeason)
+		protected.PUT("/groups/:id/seasons/:season_id/matches/:match_id/result", seasonHandler.RecordMatchResult)
+		protected.GET("/groups/:id/seasons/:season_id/standings", seasonHandler.GetStandings)
+
+		// Tournament routes
+		protected.GET("/groups/:id/tournaments", tournamentHandler.GetTournaments)
+		protected.GET("/groups/:id/tournaments/:tournament_id", tournamentHandler.GetTournament)
+		protected.POST("/groups/:id/tournaments", tournamentHandler.CreateTournament)
+		protected.DELETE("/groups/:id/tournaments/:tournament_id", tournamentHandler.DeleteTournament)
+		protected.PUT("/groups/:id/tournaments/:tournament_id/matches/:match_id/result", tournamentHandler.RecordTournamentResult)
+
 		// Team generation
 		protected.POST("/groups/:id/generate-teams", groupHandler.GenerateTeams)
+
+		// Skill weight suggestions
+		protected.GET("/groups/:id/weight-suggestions", groupHandler.GetWeightSuggestions)
+		protected.POST("/groups/:id/weight-suggestions/apply", groupHandler.ApplyWeightSuggestions)
+
+		// Player stats
+		protected.GET("/groups/:id/stats", groupHandler.GetGroupStats)
+		protected.GET("/groups/:id/teammates", groupHandler.GetTeammateMatrix)
+
+		// Game history
+		protected.GET("/groups/:id/games", gameHandler.ListGames)
+		protected.GET("/games/:shareId", gameHandler.GetGame)
+		protected.DELETE("/games/:shareId", gameHandler.DeleteGame)
+		protected.POST("/games/:shareId/rerun", gameHandler.RerunGame)
+
+		// Manual lineup edits
+		protected.POST("/games/:shareId/swap", gameHandler.SwapPlayers)
+		protected.POST("/games/:shareId/move", gameHandler.MovePlayer)
+		protected.GET("/games/:shareId/revisions", gameHandler.GetGameRevisions)
+
+		// Game results
+		protected.PUT("/games/:shareId/result", gameHandler.RecordResult)
+		protected.GET("/games/:shareId/weights", gameHandler.GetGameWeights)
+		protected.PUT("/games/:shareId/rotation", gameHandler.SetRotation)
 	}
 
 	// Serve static files from frontend build (for production)
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/auth"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// groupRole returns the user's role in a group: the higher of their membership role and their
+// role in the group's organization, capped at admin. It returns gorm.ErrRecordNotFound if they
+// have neither.
+func groupRole(db *gorm.DB, groupID, userID uint) (string, error) {
+	var roles []string
+	if err := db.Model(&models.GroupMember{}).Where("group_id = ? AND user_id = ?", groupID, userID).
+		Pluck("role", &roles).Error; err != nil {
+		return "", err
+	}
+
+	var orgRoles []string
+	if err := db.Model(&models.OrganizationMember{}).
+		Joins("JOIN groups ON groups.organization_id = organization_members.organization_id").
+		Where("groups.id = ? AND organization_members.user_id = ?", groupID, userID).
+		Pluck("organization_members.role", &orgRoles).Error; err != nil {
+		return "", err
+	}
+	// Only the group's own owner can delete it and manage its members
+	for _, role := range orgRoles {
+		roles = append(roles, capRole(role, models.GroupRoleAdmin))
+	}
+
+	best := bestRole(roles)
+	if best == "" {
+		return "", gorm.ErrRecordNotFound
+	}
+	return best, nil
+}
+
+// organizationRole returns the user's role in an organization, or gorm.ErrRecordNotFound if
+// they aren't a member
+func organizationRole(db *gorm.DB, orgID, userID uint) (string, error) {
+	var member models.OrganizationMember
+	if err := db.Where("organization_id = ? AND user_id = ?", orgID, userID).First(&member).Error; err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// bestRole returns the highest of roles, or "" if there are none
+func bestRole(roles []string) string {
+	best := ""
+	for _, role := range roles {
+		if models.RoleAtLeast(role, best) {
+			best = role
+		}
+	}
+	return best
+}
+
+// capRole lowers role to maxRole if it grants more
+func capRole(role, maxRole string) string {
+	if models.RoleAtLeast(role, maxRole) {
+		return maxRole
+	}
+	return role
+}
+
+// authorizeOrganization loads the organization from the :id param, requiring the authenticated
+// user to have at least minRole in it. It writes a 404 if the user isn't a member, a 403 if
+// their role is too low, and returns false.
+func authorizeOrganization(c *gin.Context, db *gorm.DB, minRole string) (models.Organization, bool) {
+	userID := auth.GetUserID(c)
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid organization ID"})
+		return models.Organization{}, false
+	}
+
+	role, err := organizationRole(db, uint(orgID), userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "organization not found"})
+		return models.Organization{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch organization"})
+		return models.Organization{}, false
+	}
+	if !models.RoleAtLeast(role, minRole) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient organization permissions"})
+		return models.Organization{}, false
+	}
+
+	var org models.Organization
+	if err := db.First(&org, orgID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "organization not found"})
+		return models.Organization{}, false
+	}
+	org.Role = role
+
+	return org, true
+}
+
+// authorizeGroup loads the group from the :id param, requiring the authenticated user to have
+// at least minRole in it. Pass a db with preloads to load associations. It writes a 404 if the
+// user isn't a member, a 403 if their role is too low, and returns false.
+func authorizeGroup(c *gin.Context, db *gorm.DB, minRole string) (models.Group, bool) {
+	userID := auth.GetUserID(c)
+	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
+		return models.Group{}, false
+	}
+
+	role, err := groupRole(db.Session(&gorm.Session{NewDB: true}), uint(groupID), userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return models.Group{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch group"})
+		return models.Group{}, false
+	}
+	if !models.RoleAtLeast(role, minRole) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient group permissions"})
+		return models.Group{}, false
+	}
+
+	var group models.Group
+	if err := db.First(&group, groupID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+		return models.Group{}, false
+	}
+	group.Role = role
+
+	return group, true
+}
+
+// playerRole returns the user's role for a player: owner of the players in their own account,
+// their role in the organization that owns the player, or their highest role in any group the
+// player belongs to. It returns gorm.ErrRecordNotFound if the user can't see the player.
+func playerRole(db *gorm.DB, player models.Player, userID uint) (string, error) {
+	if player.UserID == userID {
+		return models.GroupRoleOwner, nil
+	}
+
+	var groupRoles []string
+	if err := db.Model(&models.GroupMember{}).
+		Joins("JOIN group_players ON group_players.group_id = group_members.group_id").
+		Where("group_players.player_id = ? AND group_members.user_id = ?", player.ID, userID).
+		Pluck("group_members.role", &groupRoles).Error; err != nil {
+		return "", err
+	}
+
+	// Co-organizers can't act as the owner of another account's players
+	roles := make([]string, 0, len(groupRoles)+1)
+	for _, role := range groupRoles {
+		roles = append(roles, capRole(role, models.GroupRoleAdmin))
+	}
+
+	orgRole, err := organizationRole(db, player.OrganizationID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	roles = append(roles, orgRole)
+
+	best := bestRole(roles)
+	if best == "" {
+		return "", gorm.ErrRecordNotFound
+	}
+	return best, nil
+}
+
+// authorizePlayer loads the player from the :id param, requiring the authenticated user to
+// have at least minRole for it. It writes the error response and returns false if not.
+func authorizePlayer(c *gin.Context, db *gorm.DB, minRole string) (models.Player, bool) {
+	userID := auth.GetUserID(c)
+	playerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		return models.Player{}, false
+	}
+
+	var player models.Player
+	if err := db.First(&player, playerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+		return models.Player{}, false
+	}
+
+	role, err := playerRole(db, player, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+		return models.Player{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch player"})
+		return models.Player{}, false
+	}
+	if !models.RoleAtLeast(role, minRole) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions for this player"})
+		return models.Player{}, false
+	}
+
+	return player, true
+}
+
+// targetOrganization returns the organization a new player or group is created in: the
+// requested one, which the user must be at least an admin of, or their personal organization.
+// It writes the error response and returns false if the user can't create there.
+func targetOrganization(c *gin.Context, db *gorm.DB, orgID *uint) (uint, bool) {
+	userID := auth.GetUserID(c)
+
+	if orgID == nil {
+		id, err := models.PersonalOrganizationID(db, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch organization"})
+			return 0, false
+		}
+		return id, true
+	}
+
+	role, err := organizationRole(db, *orgID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "organization not found"})
+		return 0, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch organization"})
+		return 0, false
+	}
+	if !models.RoleAtLeast(role, models.GroupRoleAdmin) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient organization permissions"})
+		return 0, false
+	}
+	return *orgID, true
+}
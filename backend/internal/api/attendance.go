@@ -0,0 +1,273 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// Rosters team generation can draw players from
+const (
+	RosterAll       = "all"
+	RosterRSVPIn    = "rsvp_in"
+	RosterCheckedIn = "checked_in"
+)
+
+type RSVPRequest struct {
+	Status string `json:"status" binding:"required,oneof=in out maybe"`
+}
+
+// AttendanceCounts summarizes who is coming to a session
+type AttendanceCounts struct {
+	In         int `json:"in"`
+	Out        int `json:"out"`
+	Maybe      int `json:"maybe"`
+	Waitlisted int `json:"waitlisted"`
+	NoResponse int `json:"no_response"`
+	CheckedIn  int `json:"checked_in"`
+}
+
+// GetAttendance returns every group player's RSVP and check-in for a session, with counts
+func (h *SessionHandler) GetAttendance(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var group models.Group
+	if err := h.db.Preload("Players").First(&group, session.GroupID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch attendance"})
+		return
+	}
+
+	byPlayer, err := sessionAttendance(h.db, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch attendance"})
+		return
+	}
+
+	players := make([]gin.H, 0, len(group.Players))
+	for _, p := range group.Players {
+		entry := gin.H{
+			"player_id":     p.ID,
+			"name":          p.Name,
+			"status":        "",
+			"waitlisted_at": nil,
+			"checked_in_at": nil,
+		}
+		if a, ok := byPlayer[p.ID]; ok {
+			entry["status"] = a.Status
+			entry["waitlisted_at"] = a.WaitlistedAt
+			entry["checked_in_at"] = a.CheckedInAt
+		}
+		players = append(players, entry)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": session.ID,
+		"counts":     countAttendance(group.Players, byPlayer),
+		"players":    players,
+	})
+}
+
+// SetRSVP records whether a player is in, out or maybe for a session. Players who want in
+// to a full session are waitlisted.
+func (h *SessionHandler) SetRSVP(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req RSVPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	playerID, ok := h.findSessionPlayer(c, session)
+	if !ok {
+		return
+	}
+
+	var attendance models.Attendance
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		attendance, err = applyRSVP(tx, session, playerID, req.Status, time.Now())
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update RSVP"})
+		return
+	}
+
+	c.JSON(http.StatusOK, attendance)
+}
+
+// CheckIn marks a player as present at the rink. Checking in also counts as an RSVP of "in",
+// even for a waitlisted player in a full session.
+func (h *SessionHandler) CheckIn(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	playerID, ok := h.findSessionPlayer(c, session)
+	if !ok {
+		return
+	}
+
+	var attendance models.Attendance
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		// Lock like an RSVP so a concurrent one can't act on the status this replaces
+		if err := lockSession(tx, session.ID); err != nil {
+			return err
+		}
+		var err error
+		if attendance, err = loadAttendance(tx, session.ID, playerID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if attendance.CheckedInAt == nil {
+			attendance.CheckedInAt = &now
+		}
+		wasWaitlisted := attendance.Status == models.RSVPWaitlisted
+		attendance.Status = models.RSVPIn
+		attendance.WaitlistedAt = nil
+
+		if err := tx.Save(&attendance).Error; err != nil {
+			return err
+		}
+		if wasWaitlisted {
+			return logSessionEvent(tx, session.ID, playerID, models.SessionEventPromoted, "checked_in")
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check in player"})
+		return
+	}
+
+	c.JSON(http.StatusOK, attendance)
+}
+
+// UndoCheckIn clears a player's check-in, keeping their RSVP
+func (h *SessionHandler) UndoCheckIn(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	playerID, ok := h.findSessionPlayer(c, session)
+	if !ok {
+		return
+	}
+
+	result := h.db.Model(&models.Attendance{}).
+		Where("session_id = ? AND player_id = ? AND checked_in_at IS NOT NULL", session.ID, playerID).
+		Update("checked_in_at", nil)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to undo check-in"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player is not checked in"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "check-in removed"})
+}
+
+// findSessionPlayer parses the :player_id param and verifies the player is in the session's
+// group. It writes the error response and returns false if not.
+func (h *SessionHandler) findSessionPlayer(c *gin.Context, session models.Session) (uint, bool) {
+	playerID, err := strconv.ParseUint(c.Param("player_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		return 0, false
+	}
+
+	var count int64
+	if err := h.db.Model(&models.GroupPlayer{}).Where("group_id = ? AND player_id = ?", session.GroupID, playerID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch player"})
+		return 0, false
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not found in group"})
+		return 0, false
+	}
+
+	return uint(playerID), true
+}
+
+// loadAttendance returns a player's attendance for a session, or a new unsaved record
+func loadAttendance(db *gorm.DB, sessionID, playerID uint) (models.Attendance, error) {
+	var attendance models.Attendance
+	err := db.Where("session_id = ? AND player_id = ?", sessionID, playerID).First(&attendance).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Attendance{SessionID: sessionID, PlayerID: playerID}, nil
+	}
+	return attendance, err
+}
+
+// sessionAttendance returns a session's attendance records keyed by player ID
+func sessionAttendance(db *gorm.DB, sessionID uint) (map[uint]models.Attendance, error) {
+	var records []models.Attendance
+	if err := db.Where("session_id = ?", sessionID).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	byPlayer := make(map[uint]models.Attendance, len(records))
+	for _, a := range records {
+		byPlayer[a.PlayerID] = a
+	}
+	return byPlayer, nil
+}
+
+// countAttendance tallies RSVPs and check-ins for the given players
+func countAttendance(players []models.Player, byPlayer map[uint]models.Attendance) AttendanceCounts {
+	var counts AttendanceCounts
+	for _, p := range players {
+		a, ok := byPlayer[p.ID]
+		if !ok {
+			counts.NoResponse++
+			continue
+		}
+		switch a.Status {
+		case models.RSVPIn:
+			counts.In++
+		case models.RSVPOut:
+			counts.Out++
+		case models.RSVPMaybe:
+			counts.Maybe++
+		case models.RSVPWaitlisted:
+			counts.Waitlisted++
+		}
+		if a.CheckedInAt != nil {
+			counts.CheckedIn++
+		}
+	}
+	return counts
+}
+
+// filterRoster keeps the players who belong on a session's roster: those who RSVP'd in, or
+// those who checked in at the rink
+func filterRoster(players []models.Player, byPlayer map[uint]models.Attendance, roster string) []models.Player {
+	filtered := make([]models.Player, 0, len(players))
+	for _, p := range players {
+		a, ok := byPlayer[p.ID]
+		if !ok {
+			continue
+		}
+		if roster == RosterCheckedIn && a.CheckedInAt != nil ||
+			roster == RosterRSVPIn && a.Status == models.RSVPIn {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
@@ -60,7 +60,15 @@ func (h *AuthHandler) Signup(c *gin.Context) {
 		PasswordHash: hashedPassword,
 	}
 
-	if err := h.db.Create(&user).Error; err != nil {
+	// Every user gets a personal organization for the players and groups they create
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		_, err := models.CreatePersonalOrganization(tx, user.ID)
+		return err
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create user"})
 		return
 	}
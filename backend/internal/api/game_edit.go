@@ -0,0 +1,272 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/auth"
+	"github.com/sticktoss/backend/internal/gamedata"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SwapPlayersRequest struct {
+	PlayerID      uint `json:"player_id" binding:"required"`
+	OtherPlayerID uint `json:"other_player_id" binding:"required"`
+}
+
+type MovePlayerRequest struct {
+	PlayerID   uint `json:"player_id" binding:"required"`
+	TeamNumber int  `json:"team_number" binding:"required,min=1"`
+}
+
+// GameRevisionDetail is a revision of a game's lineup with the teams it left
+type GameRevisionDetail struct {
+	models.GameRevision
+	Teams []models.GameTeam `json:"teams"`
+}
+
+// SwapPlayers trades two players on different teams of a game
+func (h *GameHandler) SwapPlayers(c *gin.Context) {
+	game, ok := h.findGame(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req SwapPlayersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	other := req.OtherPlayerID
+	h.editLineup(c, game, models.GameRevision{Edit: models.GameEditSwap, PlayerID: req.PlayerID, OtherPlayerID: &other},
+		func(teams []models.GameTeam) error {
+			return swapPlayers(teams, req.PlayerID, req.OtherPlayerID)
+		})
+}
+
+// MovePlayer moves a player to another team of a game
+func (h *GameHandler) MovePlayer(c *gin.Context) {
+	game, ok := h.findGame(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req MovePlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.editLineup(c, game, models.GameRevision{Edit: models.GameEditMove, PlayerID: req.PlayerID, TeamNumber: req.TeamNumber},
+		func(teams []models.GameTeam) error {
+			return movePlayer(teams, req.PlayerID, req.TeamNumber)
+		})
+}
+
+// GetGameRevisions returns every revision of a game's lineup, starting with revision 0 as
+// it was generated
+func (h *GameHandler) GetGameRevisions(c *gin.Context) {
+	game, ok := h.findGame(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	generated, err := revisionTeams(game.ShareID, game.TeamsData)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load game data"})
+		return
+	}
+	original := GameRevisionDetail{
+		GameRevision: models.GameRevision{Edit: models.GameEditGenerate, CreatedAt: game.CreatedAt},
+		Teams:        generated,
+	}
+
+	var revisions []models.GameRevision
+	if err := h.db.Where("game_share_id = ?", game.ShareID).Order("number ASC").Find(&revisions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch revisions"})
+		return
+	}
+
+	result := []GameRevisionDetail{original}
+	for _, r := range revisions {
+		teams, err := revisionTeams(game.ShareID, r.TeamsData)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load game data"})
+			return
+		}
+		result = append(result, GameRevisionDetail{GameRevision: r, Teams: teams})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// editLineup applies a manual edit to a game's current teams and saves the result as the
+// game's next revision. Ratings are recalculated if the game has a result, since they
+// depend on who played on which team.
+func (h *GameHandler) editLineup(c *gin.Context, game models.Game, revision models.GameRevision, edit func([]models.GameTeam) error) {
+	var teams []models.GameTeam
+	var editErr error
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		// Concurrent edits take turns, so each applies to the lineup the last one saved and
+		// gets its own revision number
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("share_id").
+			First(&models.Game{}, "share_id = ?", game.ShareID).Error; err != nil {
+			return err
+		}
+		if err := preloadGameTeams(tx).First(&game, "share_id = ?", game.ShareID).Error; err != nil {
+			return err
+		}
+
+		teams = game.Teams
+		if editErr = edit(teams); editErr != nil {
+			return editErr
+		}
+
+		// Totals follow the weights players were balanced with when the game was generated
+		for i := range teams {
+			teams[i].TotalWeight = 0
+			for j := range teams[i].Players {
+				teams[i].Players[j].GameTeamID = teams[i].ID
+				teams[i].Players[j].Slot = j
+				teams[i].TotalWeight += teams[i].Players[j].BalanceWeight
+			}
+			teams[i].TotalWeight = math.Round(teams[i].TotalWeight*100) / 100
+		}
+
+		stored := make([]gamedata.Team, 0, len(teams))
+		for _, t := range teams {
+			stored = append(stored, t.Data())
+		}
+		data, err := gamedata.Encode(stored)
+		if err != nil {
+			return err
+		}
+		revision.GameShareID = game.ShareID
+		revision.Number = game.Revision + 1
+		revision.TeamsData = data
+		revision.EditedByUserID = auth.GetUserID(c)
+
+		if err := saveGameTeams(tx, teams); err != nil {
+			return err
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Game{}).Where("share_id = ?", game.ShareID).Update("revision", revision.Number).Error; err != nil {
+			return err
+		}
+		if game.ResultRecordedAt != nil {
+			return recomputeRatings(tx, game.OrganizationID)
+		}
+		return nil
+	})
+	if editErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": editErr.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save game"})
+		return
+	}
+
+	c.JSON(http.StatusOK, GameRevisionDetail{GameRevision: revision, Teams: teams})
+}
+
+// revisionTeams decodes the teams data of a revision
+func revisionTeams(shareID string, data []byte) ([]models.GameTeam, error) {
+	stored, _, err := gamedata.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]models.GameTeam, 0, len(stored))
+	for _, t := range stored {
+		teams = append(teams, models.NewGameTeam(shareID, t))
+	}
+	return teams, nil
+}
+
+// saveGameTeams replaces the players and totals of a game's existing teams
+func saveGameTeams(tx *gorm.DB, teams []models.GameTeam) error {
+	teamIDs := make([]uint, 0, len(teams))
+	var players []models.GameTeamPlayer
+	for _, t := range teams {
+		teamIDs = append(teamIDs, t.ID)
+		players = append(players, t.Players...)
+	}
+
+	if err := tx.Where("game_team_id IN ?", teamIDs).Delete(&models.GameTeamPlayer{}).Error; err != nil {
+		return err
+	}
+	for _, t := range teams {
+		if err := tx.Model(&models.GameTeam{}).Where("id = ?", t.ID).Update("total_weight", t.TotalWeight).Error; err != nil {
+			return err
+		}
+	}
+	if len(players) == 0 {
+		return nil
+	}
+	return tx.Create(&players).Error
+}
+
+// findGamePlayer returns the index of the team a player is on and their index on it
+func findGamePlayer(teams []models.GameTeam, playerID uint) (int, int, bool) {
+	for i, t := range teams {
+		for j, p := range t.Players {
+			if p.PlayerID == playerID {
+				return i, j, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// swapPlayers trades two players' places on their teams
+func swapPlayers(teams []models.GameTeam, a, b uint) error {
+	teamA, atA, ok := findGamePlayer(teams, a)
+	if !ok {
+		return errors.New("player is not in the game")
+	}
+	teamB, atB, ok := findGamePlayer(teams, b)
+	if !ok {
+		return errors.New("other player is not in the game")
+	}
+	if teamA == teamB {
+		return errors.New("players are already on the same team")
+	}
+
+	teams[teamA].Players[atA], teams[teamB].Players[atB] = teams[teamB].Players[atB], teams[teamA].Players[atA]
+	return nil
+}
+
+// movePlayer moves a player to the end of another team
+func movePlayer(teams []models.GameTeam, playerID uint, teamNumber int) error {
+	from, at, ok := findGamePlayer(teams, playerID)
+	if !ok {
+		return errors.New("player is not in the game")
+	}
+	to := -1
+	for i, t := range teams {
+		if t.Number == teamNumber {
+			to = i
+		}
+	}
+	if to < 0 {
+		return errors.New("invalid team number")
+	}
+	if to == from {
+		return errors.New("player is already on that team")
+	}
+	if len(teams[from].Players) == 1 {
+		return errors.New("a team can't be left without players")
+	}
+
+	player := teams[from].Players[at]
+	teams[from].Players = append(teams[from].Players[:at], teams[from].Players[at+1:]...)
+	teams[to].Players = append(teams[to].Players, player)
+	return nil
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sticktoss/backend/internal/models"
+)
+
+// lineup builds teams numbered from 1 holding the given player IDs
+func lineup(teams ...[]uint) []models.GameTeam {
+	result := make([]models.GameTeam, 0, len(teams))
+	for i, ids := range teams {
+		team := models.GameTeam{Number: i + 1}
+		for _, id := range ids {
+			team.Players = append(team.Players, models.GameTeamPlayer{PlayerID: id})
+		}
+		result = append(result, team)
+	}
+	return result
+}
+
+// playerIDs returns the player IDs on each team
+func playerIDs(teams []models.GameTeam) [][]uint {
+	result := make([][]uint, 0, len(teams))
+	for _, t := range teams {
+		ids := []uint{}
+		for _, p := range t.Players {
+			ids = append(ids, p.PlayerID)
+		}
+		result = append(result, ids)
+	}
+	return result
+}
+
+func TestSwapPlayers(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    uint
+		want    [][]uint
+		wantErr bool
+	}{
+		{name: "players keep their places", a: 2, b: 3, want: [][]uint{{1, 3}, {2, 4}}},
+		{name: "either order", a: 4, b: 1, want: [][]uint{{4, 2}, {3, 1}}},
+		{name: "same team", a: 1, b: 2, wantErr: true},
+		{name: "player not in the game", a: 9, b: 1, wantErr: true},
+		{name: "other player not in the game", a: 1, b: 9, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams := lineup([]uint{1, 2}, []uint{3, 4})
+			err := swapPlayers(teams, tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("swapPlayers() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(playerIDs(teams), tt.want) {
+				t.Errorf("swapPlayers() = %v, want %v", playerIDs(teams), tt.want)
+			}
+		})
+	}
+}
+
+func TestMovePlayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		player  uint
+		team    int
+		want    [][]uint
+		wantErr bool
+	}{
+		{name: "to the end of the team", player: 1, team: 2, want: [][]uint{{2}, {3, 4, 1}, {5}}},
+		{name: "to the last team", player: 3, team: 3, want: [][]uint{{1, 2}, {4}, {5, 3}}},
+		{name: "already on the team", player: 1, team: 1, wantErr: true},
+		{name: "no such team", player: 1, team: 4, wantErr: true},
+		{name: "player not in the game", player: 9, team: 1, wantErr: true},
+		{name: "last player on a team", player: 5, team: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teams := lineup([]uint{1, 2}, []uint{3, 4}, []uint{5})
+			err := movePlayer(teams, tt.player, tt.team)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("movePlayer() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(playerIDs(teams), tt.want) {
+				t.Errorf("movePlayer() = %v, want %v", playerIDs(teams), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/auth"
+	"github.com/sticktoss/backend/internal/gamedata"
+	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/rating"
+	"github.com/sticktoss/backend/internal/teamgen"
+	"gorm.io/gorm"
+)
+
+type GameHandler struct {
+	db *gorm.DB
+}
+
+func NewGameHandler(db *gorm.DB) *GameHandler {
+	return &GameHandler{db: db}
+}
+
+type TeamScore struct {
+	TeamNumber int `json:"team_number" binding:"required,min=1"`
+	Score      int `json:"score" binding:"min=0"`
+}
+
+type RecordResultRequest struct {
+	Scores []TeamScore `json:"scores" binding:"required,dive"`
+}
+
+// RecordResult records (or corrects) the final score of a game and updates player ratings
+func (h *GameHandler) RecordResult(c *gin.Context) {
+	game, ok := h.findGame(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req RecordResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Every team must have exactly one score
+	if len(req.Scores) != game.NumTeams {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a score is required for every team"})
+		return
+	}
+	seen := make(map[int]bool)
+	for _, s := range req.Scores {
+		if s.TeamNumber > game.NumTeams || seen[s.TeamNumber] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid team number"})
+			return
+		}
+		seen[s.TeamNumber] = true
+	}
+
+	now := time.Now()
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("game_share_id = ?", game.ShareID).Delete(&models.GameScore{}).Error; err != nil {
+			return err
+		}
+
+		scores := make([]models.GameScore, len(req.Scores))
+		for i, s := range req.Scores {
+			scores[i] = models.GameScore{
+				GameShareID: game.ShareID,
+				TeamNumber:  s.TeamNumber,
+				Score:       s.Score,
+			}
+		}
+		if err := tx.Create(&scores).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&game).Update("result_recorded_at", now).Error; err != nil {
+			return err
+		}
+
+		// Replay every result so corrections to older games flow through to later ratings
+		return recomputeRatings(tx, game.OrganizationID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record result"})
+		return
+	}
+
+	var changes []models.PlayerRating
+	if err := h.db.Where("game_share_id = ?", game.ShareID).Find(&changes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load rating changes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"share_id":       game.ShareID,
+		"scores":         req.Scores,
+		"rating_changes": changes,
+	})
+}
+
+// GetGameWeights compares the skill weights each player had when a game was generated with
+// their weights in the group today
+func (h *GameHandler) GetGameWeights(c *gin.Context) {
+	game, ok := h.findGame(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	if err := preloadGameTeams(h.db).First(&game, "share_id = ?", game.ShareID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load game data"})
+		return
+	}
+	teams := gameLineup(game)
+
+	// Current weights are the group's weights where the player has an override
+	var playerIDs []uint
+	for _, team := range teams {
+		for _, p := range team.Players {
+			playerIDs = append(playerIDs, p.ID)
+		}
+	}
+	var players []models.Player
+	if err := h.db.Where("id IN ?", playerIDs).Find(&players).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch players"})
+		return
+	}
+	if err := applySkillOverrides(h.db, game.GroupID, players); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill weights"})
+		return
+	}
+	current := make(map[uint]float64, len(players))
+	for _, p := range players {
+		current[p.ID] = p.SkillWeight
+	}
+
+	type playerWeights struct {
+		PlayerID           uint     `json:"player_id"`
+		Name               string   `json:"name"`
+		WeightAtGeneration float64  `json:"weight_at_generation"`
+		CurrentWeight      *float64 `json:"current_weight"` // nil if the player has been deleted
+		Changed            bool     `json:"changed"`
+	}
+	type teamWeights struct {
+		Number            int             `json:"number"`
+		TotalAtGeneration float64         `json:"total_at_generation"`
+		CurrentTotal      float64         `json:"current_total"`
+		Players           []playerWeights `json:"players"`
+	}
+
+	result := make([]teamWeights, 0, len(teams))
+	for _, team := range teams {
+		tw := teamWeights{Number: team.Number, Players: []playerWeights{}}
+		for _, p := range team.Players {
+			pw := playerWeights{
+				PlayerID:           p.ID,
+				Name:               p.Name,
+				WeightAtGeneration: p.SkillWeight,
+			}
+			tw.TotalAtGeneration += p.SkillWeight
+			if w, ok := current[p.ID]; ok {
+				pw.CurrentWeight = &w
+				pw.Changed = w != p.SkillWeight
+				tw.CurrentTotal += w
+			}
+			tw.Players = append(tw.Players, pw)
+		}
+		result = append(result, tw)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"share_id":   game.ShareID,
+		"created_at": game.CreatedAt,
+		"teams":      result,
+	})
+}
+
+// findGame loads the game from the :shareId param, verifying the authenticated user has at
+// least minRole in its group. It writes the error response and returns false if not.
+func (h *GameHandler) findGame(c *gin.Context, minRole string) (models.Game, bool) {
+	userID := auth.GetUserID(c)
+
+	var game models.Game
+	if err := h.db.Where("share_id = ?", c.Param("shareId")).First(&game).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+		return models.Game{}, false
+	}
+
+	role, err := groupRole(h.db, game.GroupID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+		return models.Game{}, false
+	}
+	if !models.RoleAtLeast(role, minRole) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient group permissions"})
+		return models.Game{}, false
+	}
+
+	return game, true
+}
+
+// recomputeRatings rebuilds the ratings and rating history of the players in an organization's
+// games by replaying every game with a recorded result in the order the games were generated.
+// Each player's rating starts from the skill weight they were given in the first of those games.
+func recomputeRatings(tx *gorm.DB, orgID uint) error {
+	// History from the organization's games, and from games deleted along the way, is rebuilt
+	staleHistory := func() *gorm.DB {
+		return tx.Where("game_share_id IN (?) OR game_share_id NOT IN (?)",
+			tx.Model(&models.Game{}).Select("share_id").Where("organization_id = ?", orgID),
+			tx.Model(&models.Game{}).Select("share_id"))
+	}
+
+	var games []models.Game
+	if err := preloadGameTeams(tx).Preload("Scores").
+		Where("organization_id = ? AND result_recorded_at IS NOT NULL", orgID).
+		Order("created_at ASC").
+		Find(&games).Error; err != nil {
+		return err
+	}
+
+	// Players with history from these games are recalculated too, so anyone edited out of
+	// every lineup loses the rating those games gave them
+	var playerIDs []uint
+	if err := staleHistory().Model(&models.PlayerRating{}).Distinct().Pluck("player_id", &playerIDs).Error; err != nil {
+		return err
+	}
+	lineupIDs := []uint{}
+	for _, game := range games {
+		for _, team := range game.Teams {
+			for _, p := range team.Players {
+				lineupIDs = append(lineupIDs, p.PlayerID)
+			}
+		}
+	}
+	// Players deleted since a game was generated no longer have a rating
+	exists := make(map[uint]bool)
+	if len(lineupIDs) > 0 {
+		var ids []uint
+		if err := tx.Model(&models.Player{}).Where("id IN ?", lineupIDs).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		for _, id := range ids {
+			exists[id] = true
+		}
+		playerIDs = append(playerIDs, ids...)
+	}
+
+	// Lineup weights are on the skill scale of the game's group
+	scales := make(map[uint]models.SkillScale)
+	ratings := make(map[uint]float64)
+	history := []models.PlayerRating{}
+	played := make(map[uint]bool)
+	for _, game := range games {
+		scale, ok := scales[game.GroupID]
+		if !ok {
+			var err error
+			if scale, err = gameSkillScale(tx, game); err != nil {
+				return err
+			}
+			scales[game.GroupID] = scale
+		}
+		teams := gameLineup(game)
+
+		scoreByTeam := make(map[int]int)
+		for _, s := range game.Scores {
+			scoreByTeam[s.TeamNumber] = s.Score
+		}
+
+		results := make([]rating.TeamResult, 0, len(teams))
+		for _, team := range teams {
+			result := rating.TeamResult{Score: scoreByTeam[team.Number]}
+			for _, p := range team.Players {
+				if !exists[p.ID] {
+					continue
+				}
+				if _, ok := ratings[p.ID]; !ok {
+					ratings[p.ID] = rating.Seed(scale.Normalize(p.SkillWeight))
+				}
+				result.PlayerIDs = append(result.PlayerIDs, p.ID)
+			}
+			results = append(results, result)
+		}
+
+		for playerID, after := range rating.Update(ratings, results) {
+			history = append(history, models.PlayerRating{
+				PlayerID:     playerID,
+				GameShareID:  game.ShareID,
+				RatingBefore: ratings[playerID],
+				RatingAfter:  after,
+				PlayedAt:     game.CreatedAt,
+			})
+			ratings[playerID] = after
+			played[playerID] = true
+		}
+	}
+
+	if err := staleHistory().Delete(&models.PlayerRating{}).Error; err != nil {
+		return err
+	}
+	if len(history) > 0 {
+		if err := tx.CreateInBatches(&history, 100).Error; err != nil {
+			return err
+		}
+	}
+
+	updated := make(map[uint]bool, len(playerIDs))
+	for _, id := range playerIDs {
+		if updated[id] {
+			continue
+		}
+		updated[id] = true
+		// Players without any recorded games keep no learned rating
+		newRating := 0.0
+		if played[id] {
+			newRating = ratings[id]
+		}
+		if err := tx.Model(&models.Player{}).Where("id = ?", id).Update("rating", newRating).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// gameSkillScale returns the skill scale of a game's group, or of the account that generated
+// the game if the group is gone
+func gameSkillScale(db *gorm.DB, game models.Game) (models.SkillScale, error) {
+	var group models.Group
+	err := db.Select("id", "user_id", "skill_scale_id").First(&group, game.GroupID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return accountSkillScale(db, game.UserID)
+	}
+	if err != nil {
+		return models.SkillScale{}, err
+	}
+	return groupSkillScale(db, group)
+}
+
+// effectiveRating returns a player's learned rating, or the seed from their skill weight
+// (on the given scale) if they have not played a recorded game yet
+func effectiveRating(p models.Player, scale models.SkillScale) float64 {
+	if p.Rating == 0 {
+		return rating.Seed(scale.Normalize(p.SkillWeight))
+	}
+	return p.Rating
+}
+
+// balancingWeights returns the weights teamgen should balance on for a group, or nil when
+// the group uses manual skill weights. Weights are on the group's skill scale.
+func balancingWeights(group models.Group, players []models.Player, scale models.SkillScale) map[uint]float64 {
+	if group.TeamBalancing != models.TeamBalancingRating && group.TeamBalancing != models.TeamBalancingBlended {
+		return nil
+	}
+
+	weights := make(map[uint]float64, len(players))
+	for _, p := range players {
+		ratingWeight := scale.Denormalize(rating.ToWeight(effectiveRating(p, scale)))
+		if group.TeamBalancing == models.TeamBalancingRating {
+			weights[p.ID] = ratingWeight
+		} else {
+			weights[p.ID] = (1-group.RatingBlend)*p.SkillWeight + group.RatingBlend*ratingWeight
+		}
+	}
+	return weights
+}
+
+// preloadGameTeams loads games with their teams and players in order
+func preloadGameTeams(db *gorm.DB) *gorm.DB {
+	return db.Preload("Teams", func(db *gorm.DB) *gorm.DB {
+		return db.Order("number ASC")
+	}).Preload("Teams.Players", func(db *gorm.DB) *gorm.DB {
+		return db.Order("slot ASC")
+	})
+}
+
+// teamData snapshots a generated team for storing in teams data, with the weights its
+// players were balanced with
+func teamData(team teamgen.Team, weights map[uint]float64) gamedata.Team {
+	result := gamedata.Team{Number: team.Number, TotalWeight: team.TotalWeight, Players: make([]gamedata.Player, 0, len(team.Players))}
+	for _, p := range team.Players {
+		weight, ok := weights[p.ID]
+		if !ok {
+			weight = p.SkillWeight
+		}
+		result.Players = append(result.Players, gamedata.Player{
+			ID:            p.ID,
+			Name:          p.Name,
+			SkillWeight:   p.SkillWeight,
+			BalanceWeight: weight,
+			Position:      p.Position,
+		})
+	}
+	return result
+}
+
+// gameLineup returns a game's current teams, with each player as they were when it was generated
+func gameLineup(game models.Game) []teamgen.Team {
+	teams := make([]teamgen.Team, 0, len(game.Teams))
+	for _, t := range game.Teams {
+		team := teamgen.Team{Number: t.Number, TotalWeight: t.TotalWeight, Players: make([]models.Player, 0, len(t.Players))}
+		for _, p := range t.Players {
+			team.Players = append(team.Players, models.Player{ID: p.PlayerID, Name: p.Name, SkillWeight: p.SkillWeight, Position: p.Position})
+		}
+		teams = append(teams, team)
+	}
+	return teams
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/rotation"
+	"gorm.io/gorm"
+)
+
+// Page sizes for the game history
+const (
+	defaultGamesPerPage = 20
+	maxGamesPerPage     = 100
+)
+
+// ListGames returns a page of a group's games, newest first, optionally limited to games
+// generated in a date range
+func (h *GameHandler) ListGames(c *gin.Context) {
+	page, perPage := 1, defaultGamesPerPage
+	if value := c.Query("page"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid page"})
+			return
+		}
+		page = n
+	}
+	if value := c.Query("per_page"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxGamesPerPage {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "per_page must be between 1 and 100"})
+			return
+		}
+		perPage = n
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	query := h.db.Model(&models.Game{}).Where("group_id = ?", group.ID)
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date"})
+			return
+		}
+		query = query.Where("created_at >= ?", t)
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date"})
+			return
+		}
+		query = query.Where("created_at < ?", t)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch games"})
+		return
+	}
+
+	var games []models.Game
+	if err := query.Preload("Scores").
+		Select("share_id", "session_id", "num_teams", "revision", "result_recorded_at", "created_at").
+		Order("created_at DESC").Offset((page - 1) * perPage).Limit(perPage).
+		Find(&games).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch games"})
+		return
+	}
+
+	summaries := make([]gin.H, 0, len(games))
+	for _, g := range games {
+		summaries = append(summaries, gin.H{
+			"share_id":           g.ShareID,
+			"session_id":         g.SessionID,
+			"num_teams":          g.NumTeams,
+			"revision":           g.Revision,
+			"scores":             g.Scores,
+			"result_recorded_at": g.ResultRecordedAt,
+			"created_at":         g.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"games":    summaries,
+		"total":    total,
+		"page":     page,
+		"per_page": perPage,
+	})
+}
+
+// GetGame returns a game with its teams, rotation and result to members of its group
+func (h *GameHandler) GetGame(c *gin.Context) {
+	game, ok := h.findGame(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	if err := preloadGameTeams(h.db).Preload("Scores", func(db *gorm.DB) *gorm.DB {
+		return db.Order("team_number ASC")
+	}).First(&game, "share_id = ?", game.ShareID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load game data"})
+		return
+	}
+
+	var rot *rotation.Rotation
+	if len(game.RotationData) > 0 {
+		if err := json.Unmarshal(game.RotationData, &rot); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load game data"})
+			return
+		}
+	}
+
+	var generation *GenerationInputs
+	if len(game.GenerationData) > 0 {
+		if err := json.Unmarshal(game.GenerationData, &generation); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load game data"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"share_id":           game.ShareID,
+		"group_id":           game.GroupID,
+		"session_id":         game.SessionID,
+		"num_teams":          game.NumTeams,
+		"use_jersey_colors":  game.UseJerseyColors,
+		"teams":              game.Teams,
+		"revision":           game.Revision,
+		"rotation":           rot,
+		"scores":             game.Scores,
+		"generation":         generation,
+		"result_recorded_at": game.ResultRecordedAt,
+		"created_at":         game.CreatedAt,
+	})
+}
+
+// DeleteGame deletes a game and its result, recalculating ratings without it. Games that
+// tournaments were created from can't be deleted until the tournaments are.
+func (h *GameHandler) DeleteGame(c *gin.Context) {
+	game, ok := h.findGame(c, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	var tournaments int64
+	if err := h.db.Model(&models.Tournament{}).Where("game_share_id = ?", game.ShareID).Count(&tournaments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete game"})
+		return
+	}
+	if tournaments > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "game has tournaments; delete them first"})
+		return
+	}
+
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		return deleteGames(tx, []models.Game{game})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete game"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "game deleted"})
+}
+
+// deleteGames deletes games with their teams, revisions, scores and rating history, then
+// recalculates the ratings of each organization that loses a recorded result
+func deleteGames(tx *gorm.DB, games []models.Game) error {
+	if len(games) == 0 {
+		return nil
+	}
+
+	shareIDs := make([]string, 0, len(games))
+	orgIDs := make(map[uint]bool)
+	for _, g := range games {
+		shareIDs = append(shareIDs, g.ShareID)
+		if g.ResultRecordedAt != nil {
+			orgIDs[g.OrganizationID] = true
+		}
+	}
+
+	if err := tx.Where("game_team_id IN (?)", tx.Model(&models.GameTeam{}).Select("id").Where("game_share_id IN ?", shareIDs)).
+		Delete(&models.GameTeamPlayer{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("game_share_id IN ?", shareIDs).Delete(&models.GameTeam{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("game_share_id IN ?", shareIDs).Delete(&models.GameRevision{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("game_share_id IN ?", shareIDs).Delete(&models.GameScore{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("share_id IN ?", shareIDs).Delete(&models.Game{}).Error; err != nil {
+		return err
+	}
+
+	// Recalculating drops the deleted games' rating history
+	for orgID := range orgIDs {
+		if err := recomputeRatings(tx, orgID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+)
+
+// RerunGame generates a fresh lineup as a new game with the same settings, strategy and
+// players as an earlier one. Players who have since left the group are left out, and the
+// players' current skill weights are used. The response flags when the balancing algorithm
+// has changed since the original was generated.
+func (h *GameHandler) RerunGame(c *gin.Context) {
+	game, ok := h.findGame(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+	if len(game.GenerationData) == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "game was generated before its settings were saved"})
+		return
+	}
+
+	var inputs GenerationInputs
+	if err := json.Unmarshal(game.GenerationData, &inputs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load game data"})
+		return
+	}
+	inputs.RerunOf = game.ShareID
+	// The session may have been deleted since, which unlinks the game from it
+	inputs.Request.SessionID = game.SessionID
+	if inputs.PlayerIDs == nil {
+		inputs.PlayerIDs = []uint{}
+	}
+
+	var group models.Group
+	if err := h.db.Preload("Players").First(&group, game.GroupID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load group"})
+		return
+	}
+
+	generateGame(c, h.db, group, inputs)
+}
@@ -2,13 +2,16 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sticktoss/backend/internal/auth"
+	"github.com/sticktoss/backend/internal/gamedata"
 	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/rotation"
 	"github.com/sticktoss/backend/internal/teamgen"
 	"github.com/sticktoss/backend/internal/utils"
 	"gorm.io/gorm"
@@ -23,49 +26,131 @@ func NewGroupHandler(db *gorm.DB) *GroupHandler {
 }
 
 type CreateGroupRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name           string `json:"name" binding:"required"`
+	OrganizationID *uint  `json:"organization_id"` // Optional, defaults to the user's personal organization
 }
 
 type UpdateGroupRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name              string   `json:"name" binding:"required"`
+	TeamBalancing     string   `json:"team_balancing" binding:"omitempty,oneof=manual rating blended"`                       // Optional, keeps the current mode when empty
+	RatingBlend       *float64 `json:"rating_blend" binding:"omitempty,min=0,max=1"`                                         // Optional, share of the learned rating when blended
+	WaitlistPriority  string   `json:"waitlist_priority" binding:"omitempty,oneof=first_come regulars lottery least_recent"` // Optional, keeps the current rule when empty
+	LateCancelHours   *int     `json:"late_cancel_hours" binding:"omitempty,min=0"`                                          // Optional
+	NoShowPenaltyRate *float64 `json:"no_show_penalty_rate" binding:"omitempty,min=0,max=1"`                                 // Optional, 0 turns the penalty off
+	GoaliesFree       *bool    `json:"goalies_free"`                                                                         // Optional
+	SpareShare        *float64 `json:"spare_share" binding:"omitempty,min=0,max=1"`                                          // Optional
+	RegularsAlwaysPay *bool    `json:"regulars_always_pay"`                                                                  // Optional
+}
+
+type MoveGroupRequest struct {
+	OrganizationID uint `json:"organization_id" binding:"required"`
 }
 
 type AddPlayerToGroupRequest struct {
-	PlayerID uint `json:"player_id" binding:"required"`
+	PlayerID    uint     `json:"player_id" binding:"required"`
+	SkillWeight *float64 `json:"skill_weight"` // Optional group skill weight, required if the player's weight doesn't fit the group's scale
+}
+
+type SetRegularRequest struct {
+	IsRegular bool `json:"is_regular"`
+}
+
+type SetGroupSkillWeightRequest struct {
+	SkillWeight *float64 `json:"skill_weight"` // nil clears the override
 }
 
 type GenerateTeamsRequest struct {
 	NumTeams         int      `json:"num_teams" binding:"required,min=2"`
-	LockedPlayers    [][]uint `json:"locked_players"`     // Array of arrays, each inner array is players that should be on same team
-	SeparatedPlayers [][]uint `json:"separated_players"`  // Array of arrays, each inner array is players that should be on different teams
-	UseJerseyColors  bool     `json:"use_jersey_colors"`  // Whether to use jersey colors (Light/Dark)
+	LockedPlayers    [][]uint `json:"locked_players"`                                          // Array of arrays, each inner array is players that should be on same team
+	SeparatedPlayers [][]uint `json:"separated_players"`                                       // Array of arrays, each inner array is players that should be on different teams
+	UseJerseyColors  bool     `json:"use_jersey_colors"`                                       // Whether to use jersey colors (Light/Dark)
+	SessionID        *uint    `json:"session_id"`                                              // Optional session the game is being generated for
+	Roster           string   `json:"roster" binding:"omitempty,oneof=all rsvp_in checked_in"` // Which players to use: "all" (default), "rsvp_in" or "checked_in" for the session
+	PeriodMinutes    int      `json:"period_minutes" binding:"omitempty,min=1"`                // Optional, builds an on-ice rotation for three or more teams
+	SessionMinutes   int      `json:"session_minutes" binding:"omitempty,min=1"`               // Optional, defaults to the length of the session
+}
+
+// GenerationInputs is everything a game's teams were generated from, kept so the game can be
+// rerun with the same settings
+type GenerationInputs struct {
+	AlgorithmVersion int                  `json:"algorithm_version"`
+	Request          GenerateTeamsRequest `json:"request"`
+	TeamBalancing    string               `json:"team_balancing"` // The group's balancing strategy at the time
+	RatingBlend      float64              `json:"rating_blend"`
+	PlayerIDs        []uint               `json:"player_ids"`         // The roster the teams were drawn from
+	RerunOf          string               `json:"rerun_of,omitempty"` // The game these settings were rerun from
 }
 
-// GetGroups returns all groups for the authenticated user
+// GetGroups returns all groups the authenticated user is a member of or that are owned by
+// their organizations
 func (h *GroupHandler) GetGroups(c *gin.Context) {
 	userID := auth.GetUserID(c)
 
+	var members []models.GroupMember
+	if err := h.db.Where("user_id = ?", userID).Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch groups"})
+		return
+	}
+	roles := make(map[uint]string, len(members))
+	groupIDs := make([]uint, 0, len(members))
+	for _, m := range members {
+		roles[m.GroupID] = m.Role
+		groupIDs = append(groupIDs, m.GroupID)
+	}
+
+	var orgMembers []models.OrganizationMember
+	if err := h.db.Where("user_id = ?", userID).Find(&orgMembers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch groups"})
+		return
+	}
+	orgRoles := make(map[uint]string, len(orgMembers))
+	orgIDs := make([]uint, 0, len(orgMembers))
+	for _, m := range orgMembers {
+		orgRoles[m.OrganizationID] = m.Role
+		orgIDs = append(orgIDs, m.OrganizationID)
+	}
+
+	query := h.db.Where("id IN ? OR organization_id IN ?", groupIDs, orgIDs)
+	if orgID := c.Query("organization_id"); orgID != "" {
+		query = query.Where("organization_id = ?", orgID)
+	}
+
 	var groups []models.Group
-	if err := h.db.Where("user_id = ?", userID).Find(&groups).Error; err != nil {
+	if err := query.Find(&groups).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch groups"})
 		return
 	}
+	for i := range groups {
+		// Matches groupRole: organization roles grant at most admin in a group
+		orgRole := capRole(orgRoles[groups[i].OrganizationID], models.GroupRoleAdmin)
+		groups[i].Role = bestRole([]string{roles[groups[i].ID], orgRole})
+	}
 
 	c.JSON(http.StatusOK, groups)
 }
 
 // GetGroup returns a single group by ID with its players
 func (h *GroupHandler) GetGroup(c *gin.Context) {
-	userID := auth.GetUserID(c)
-	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	group, ok := authorizeGroup(c, h.db.Preload("Players"), models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	overrides, err := skillOverrides(h.db, group.ID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch group"})
 		return
 	}
+	group.SkillOverrides = overrides
 
-	var group models.Group
-	if err := h.db.Preload("Players").Where("id = ? AND user_id = ?", groupID, userID).First(&group).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+	if err := h.db.Model(&models.GroupPlayer{}).Where("group_id = ? AND is_regular = ?", group.ID, true).
+		Pluck("player_id", &group.Regulars).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch group"})
+		return
+	}
+	if err := h.db.Model(&models.GroupPlayer{}).Where("group_id = ? AND is_spare = ?", group.ID, true).
+		Pluck("player_id", &group.Spares).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch group"})
 		return
 	}
 
@@ -82,31 +167,36 @@ func (h *GroupHandler) CreateGroup(c *gin.Context) {
 		return
 	}
 
+	orgID, ok := targetOrganization(c, h.db, req.OrganizationID)
+	if !ok {
+		return
+	}
+
 	group := models.Group{
-		UserID: userID,
-		Name:   req.Name,
+		UserID:         userID,
+		OrganizationID: orgID,
+		Name:           req.Name,
 	}
 
-	if err := h.db.Create(&group).Error; err != nil {
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&group).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.GroupMember{GroupID: group.ID, UserID: userID, Role: models.GroupRoleOwner}).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create group"})
 		return
 	}
+	group.Role = models.GroupRoleOwner
 
 	c.JSON(http.StatusCreated, group)
 }
 
 // UpdateGroup updates an existing group
 func (h *GroupHandler) UpdateGroup(c *gin.Context) {
-	userID := auth.GetUserID(c)
-	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
-		return
-	}
-
-	var group models.Group
-	if err := h.db.Where("id = ? AND user_id = ?", groupID, userID).First(&group).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
 		return
 	}
 
@@ -117,6 +207,30 @@ func (h *GroupHandler) UpdateGroup(c *gin.Context) {
 	}
 
 	group.Name = req.Name
+	if req.TeamBalancing != "" {
+		group.TeamBalancing = req.TeamBalancing
+	}
+	if req.RatingBlend != nil {
+		group.RatingBlend = *req.RatingBlend
+	}
+	if req.WaitlistPriority != "" {
+		group.WaitlistPriority = req.WaitlistPriority
+	}
+	if req.LateCancelHours != nil {
+		group.LateCancelHours = *req.LateCancelHours
+	}
+	if req.NoShowPenaltyRate != nil {
+		group.NoShowPenaltyRate = *req.NoShowPenaltyRate
+	}
+	if req.GoaliesFree != nil {
+		group.GoaliesFree = *req.GoaliesFree
+	}
+	if req.SpareShare != nil {
+		group.SpareShare = *req.SpareShare
+	}
+	if req.RegularsAlwaysPay != nil {
+		group.RegularsAlwaysPay = *req.RegularsAlwaysPay
+	}
 
 	if err := h.db.Save(&group).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update group"})
@@ -128,35 +242,148 @@ func (h *GroupHandler) UpdateGroup(c *gin.Context) {
 
 // DeleteGroup deletes a group
 func (h *GroupHandler) DeleteGroup(c *gin.Context) {
-	userID := auth.GetUserID(c)
-	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("schedule_id IN (?)", tx.Model(&models.SessionSchedule{}).Select("id").Where("group_id = ?", group.ID)).
+			Delete(&models.SessionScheduleException{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", group.ID).Delete(&models.SessionSchedule{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("session_id IN (?)", tx.Model(&models.Session{}).Select("id").Where("group_id = ?", group.ID)).
+			Delete(&models.Attendance{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("session_id IN (?)", tx.Model(&models.Session{}).Select("id").Where("group_id = ?", group.ID)).
+			Delete(&models.SessionEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", group.ID).Delete(&models.Session{}).Error; err != nil {
+			return err
+		}
+		var seasonIDs []uint
+		if err := tx.Model(&models.Season{}).Where("group_id = ?", group.ID).Pluck("id", &seasonIDs).Error; err != nil {
+			return err
+		}
+		if err := deleteSeasons(tx, seasonIDs); err != nil {
+			return err
+		}
+		var tournamentIDs []uint
+		if err := tx.Model(&models.Tournament{}).Where("group_id = ?", group.ID).Pluck("id", &tournamentIDs).Error; err != nil {
+			return err
+		}
+		if err := deleteTournaments(tx, tournamentIDs); err != nil {
+			return err
+		}
+		var games []models.Game
+		if err := tx.Select("share_id", "organization_id", "result_recorded_at").Where("group_id = ?", group.ID).
+			Find(&games).Error; err != nil {
+			return err
+		}
+		if err := deleteGames(tx, games); err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", group.ID).Delete(&models.LedgerEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", group.ID).Delete(&models.GroupInvite{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", group.ID).Delete(&models.GroupMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&group).Error
+	})
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete group"})
 		return
 	}
 
-	var group models.Group
-	if err := h.db.Where("id = ? AND user_id = ?", groupID, userID).First(&group).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+	c.JSON(http.StatusOK, gin.H{"message": "group deleted"})
+}
+
+// MoveGroup moves a group, its games and its players into another organization. The user
+// must be an admin of both organizations. Players the old organization also uses in its
+// other groups stay behind and remain in the group.
+func (h *GroupHandler) MoveGroup(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	var req MoveGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.db.Delete(&group).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete group"})
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "group deleted"})
+	// Group admins who aren't organization admins can't take the group's players with them
+	role, err := organizationRole(h.db, group.OrganizationID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch organization"})
+		return
+	}
+	if !models.RoleAtLeast(role, models.GroupRoleAdmin) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient organization permissions"})
+		return
+	}
+
+	if req.OrganizationID == group.OrganizationID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "group is already in this organization"})
+		return
+	}
+	orgID, ok := targetOrganization(c, h.db, &req.OrganizationID)
+	if !ok {
+		return
+	}
+
+	fromOrgID := group.OrganizationID
+	movedPlayerIDs := []uint{}
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Player{}).
+			Where("organization_id = ? AND id IN (?)", fromOrgID, tx.Model(&models.GroupPlayer{}).Select("player_id").Where("group_id = ?", group.ID)).
+			Where("NOT EXISTS (SELECT 1 FROM group_players JOIN groups ON groups.id = group_players.group_id "+
+				"WHERE group_players.player_id = players.id AND groups.id <> ? AND groups.organization_id = ?)", group.ID, fromOrgID).
+			Pluck("id", &movedPlayerIDs).Error; err != nil {
+			return err
+		}
+		if len(movedPlayerIDs) > 0 {
+			if err := tx.Model(&models.Player{}).Where("id IN ?", movedPlayerIDs).Update("organization_id", orgID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.Game{}).Where("group_id = ?", group.ID).Update("organization_id", orgID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Group{}).Where("id = ?", group.ID).Update("organization_id", orgID).Error; err != nil {
+			return err
+		}
+
+		// Ratings are learned from all of an organization's games, so both sides change
+		if err := recomputeRatings(tx, fromOrgID); err != nil {
+			return err
+		}
+		return recomputeRatings(tx, orgID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to move group"})
+		return
+	}
+	group.OrganizationID = orgID
+
+	c.JSON(http.StatusOK, gin.H{"group": group, "moved_player_ids": movedPlayerIDs})
 }
 
 // AddPlayerToGroup adds a player to a group
 func (h *GroupHandler) AddPlayerToGroup(c *gin.Context) {
 	userID := auth.GetUserID(c)
-	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
-		return
-	}
 
 	var req AddPlayerToGroupRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -164,76 +391,264 @@ func (h *GroupHandler) AddPlayerToGroup(c *gin.Context) {
 		return
 	}
 
-	// Verify group belongs to user
-	var group models.Group
-	if err := h.db.Where("id = ? AND user_id = ?", groupID, userID).First(&group).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
 		return
 	}
 
-	// Verify player belongs to user
+	// Players can come from the group's organization, the adding user's account or the group owner's
 	var player models.Player
-	if err := h.db.Where("id = ? AND user_id = ?", req.PlayerID, userID).First(&player).Error; err != nil {
+	if err := h.db.Where("id = ? AND (organization_id = ? OR user_id IN ?)", req.PlayerID, group.OrganizationID, []uint{userID, group.UserID}).
+		First(&player).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
 		return
 	}
 
+	// The weight the player will have in this group must fit the group's scale
+	scale, err := groupSkillScale(h.db, group)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill scale"})
+		return
+	}
+	if req.SkillWeight != nil && !scale.Contains(*req.SkillWeight) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "skill weight is not on the group's skill scale"})
+		return
+	}
+	if req.SkillWeight == nil && !scale.Contains(player.SkillWeight) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "player's skill weight is not on the group's skill scale, a group skill weight is required"})
+		return
+	}
+
 	// Add player to group (GORM handles the many-to-many)
 	if err := h.db.Model(&group).Association("Players").Append(&player); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add player to group"})
 		return
 	}
 
+	if req.SkillWeight != nil {
+		err := h.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.GroupPlayer{}).Where("group_id = ? AND player_id = ?", group.ID, player.ID).
+				Update("skill_weight", *req.SkillWeight).Error; err != nil {
+				return err
+			}
+			return recordWeightChange(tx, player.ID, &group.ID, nil, req.SkillWeight, userID, models.WeightSourceManual)
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set skill weight"})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "player added to group"})
 }
 
 // RemovePlayerFromGroup removes a player from a group
 func (h *GroupHandler) RemovePlayerFromGroup(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("player_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var player models.Player
+	if err := h.db.Where("id = ?", playerID).First(&player).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+		return
+	}
+
+	// Remove player from group, giving up their spots in upcoming sessions
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&group).Association("Players").Delete(&player); err != nil {
+			return err
+		}
+		return withdrawPlayer(tx, player.ID, []uint{group.ID}, time.Now())
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove player from group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "player removed from group"})
+}
+
+// SetGroupSkillWeight sets or clears a player's skill weight override for one group
+func (h *GroupHandler) SetGroupSkillWeight(c *gin.Context) {
 	userID := auth.GetUserID(c)
-	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	playerID, err := strconv.ParseUint(c.Param("player_id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
 		return
 	}
 
+	var req SetGroupSkillWeightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var membership models.GroupPlayer
+	if err := h.db.Where("group_id = ? AND player_id = ?", group.ID, playerID).First(&membership).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not in group"})
+		return
+	}
+
+	if req.SkillWeight != nil {
+		scale, err := groupSkillScale(h.db, group)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill scale"})
+			return
+		}
+		if !scale.Contains(*req.SkillWeight) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "skill weight is not on the group's skill scale"})
+			return
+		}
+	}
+
+	oldWeight := membership.SkillWeight
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&membership).Where("group_id = ? AND player_id = ?", group.ID, playerID).
+			Update("skill_weight", req.SkillWeight).Error; err != nil {
+			return err
+		}
+		if sameWeight(oldWeight, req.SkillWeight) {
+			return nil
+		}
+		return recordWeightChange(tx, membership.PlayerID, &group.ID, oldWeight, req.SkillWeight, userID, models.WeightSourceManual)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update skill weight"})
+		return
+	}
+	membership.SkillWeight = req.SkillWeight
+
+	c.JSON(http.StatusOK, membership)
+}
+
+// SetRegular marks a player as one of the group's regulars, or clears it
+func (h *GroupHandler) SetRegular(c *gin.Context) {
 	playerID, err := strconv.ParseUint(c.Param("player_id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
 		return
 	}
 
-	// Verify group belongs to user
-	var group models.Group
-	if err := h.db.Where("id = ? AND user_id = ?", groupID, userID).First(&group).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+	var req SetRegularRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Verify player belongs to user
-	var player models.Player
-	if err := h.db.Where("id = ? AND user_id = ?", playerID, userID).First(&player).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
 		return
 	}
 
-	// Remove player from group
-	if err := h.db.Model(&group).Association("Players").Delete(&player); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove player from group"})
+	var membership models.GroupPlayer
+	if err := h.db.Where("group_id = ? AND player_id = ?", group.ID, playerID).First(&membership).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not in group"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "player removed from group"})
+	// Regulars can't also be spares
+	updates := map[string]interface{}{"is_regular": req.IsRegular}
+	if req.IsRegular {
+		updates["is_spare"] = false
+	}
+	if err := h.db.Model(&membership).Where("group_id = ? AND player_id = ?", group.ID, playerID).
+		Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update player"})
+		return
+	}
+	membership.IsRegular = req.IsRegular
+	if req.IsRegular {
+		membership.IsSpare = false
+	}
+
+	c.JSON(http.StatusOK, membership)
 }
 
-// GenerateTeams generates balanced teams for a group
-func (h *GroupHandler) GenerateTeams(c *gin.Context) {
+// GetGroupSkillScale returns the skill scale in effect for a group
+func (h *GroupHandler) GetGroupSkillScale(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	scale, err := groupSkillScale(h.db, group)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill scale"})
+		return
+	}
+
+	c.JSON(http.StatusOK, scale)
+}
+
+// SetGroupSkillScale sets the skill scale used by a group, reporting any players whose
+// weight in the group no longer fits
+func (h *GroupHandler) SetGroupSkillScale(c *gin.Context) {
 	userID := auth.GetUserID(c)
-	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+
+	var req SetSkillScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db.Preload("Players"), models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	if req.SkillScaleID != nil {
+		var scale models.SkillScale
+		if err := h.db.Where("id = ? AND user_id IN ?", *req.SkillScaleID, []uint{userID, group.UserID}).First(&scale).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "skill scale not found"})
+			return
+		}
+	}
+
+	if err := h.db.Model(&group).Update("skill_scale_id", req.SkillScaleID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set skill scale"})
+		return
+	}
+	group.SkillScaleID = req.SkillScaleID
+
+	scale, err := groupSkillScale(h.db, group)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill scale"})
+		return
+	}
+	if err := applySkillOverrides(h.db, group.ID, group.Players); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill weights"})
 		return
 	}
 
+	outOfRange := []uint{}
+	for _, p := range group.Players {
+		if !scale.Contains(p.SkillWeight) {
+			outOfRange = append(outOfRange, p.ID)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"skill_scale":             scale,
+		"out_of_range_player_ids": outOfRange,
+	})
+}
+
+// GenerateTeams generates balanced teams for a group
+func (h *GroupHandler) GenerateTeams(c *gin.Context) {
 	var req GenerateTeamsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -241,29 +656,139 @@ func (h *GroupHandler) GenerateTeams(c *gin.Context) {
 	}
 
 	// Get group with players
-	var group models.Group
-	if err := h.db.Preload("Players").Where("id = ? AND user_id = ?", groupID, userID).First(&group).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+	group, ok := authorizeGroup(c, h.db.Preload("Players"), models.GroupRoleAdmin)
+	if !ok {
 		return
 	}
 
+	generateGame(c, h.db, group, GenerationInputs{Request: req})
+}
+
+// generateGame generates balanced teams for a group from the given inputs and saves them as
+// a new game. The roster and balancing strategy come from the inputs when they are set, as
+// when rerunning a game, and are otherwise resolved from the request and the group.
+func generateGame(c *gin.Context, db *gorm.DB, group models.Group, inputs GenerationInputs) {
+	req := inputs.Request
+	if inputs.TeamBalancing != "" {
+		group.TeamBalancing = inputs.TeamBalancing
+		group.RatingBlend = inputs.RatingBlend
+	} else {
+		inputs.TeamBalancing = group.TeamBalancing
+		inputs.RatingBlend = group.RatingBlend
+	}
+
+	if req.SessionID != nil {
+		var session models.Session
+		if err := db.Where("id = ? AND group_id = ?", *req.SessionID, group.ID).First(&session).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			return
+		}
+	}
+
 	if len(group.Players) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "group has no players"})
 		return
 	}
 
+	// A rerun draws from the same players, as long as they are still in the group
+	if inputs.PlayerIDs != nil {
+		inRoster := make(map[uint]bool, len(inputs.PlayerIDs))
+		for _, id := range inputs.PlayerIDs {
+			inRoster[id] = true
+		}
+		players := make([]models.Player, 0, len(inputs.PlayerIDs))
+		for _, p := range group.Players {
+			if inRoster[p.ID] {
+				players = append(players, p)
+			}
+		}
+		group.Players = players
+	} else {
+		// Limit the players to those attending the session if requested. Spares only play when
+		// they are attending.
+		if req.Roster == "" || req.Roster == RosterAll {
+			spares, err := spareIDs(db, group.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load players"})
+				return
+			}
+			players := make([]models.Player, 0, len(group.Players))
+			for _, p := range group.Players {
+				if !spares[p.ID] {
+					players = append(players, p)
+				}
+			}
+			group.Players = players
+		} else {
+			if req.SessionID == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "session_id is required for this roster"})
+				return
+			}
+			byPlayer, err := sessionAttendance(db, *req.SessionID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load attendance"})
+				return
+			}
+			group.Players = filterRoster(group.Players, byPlayer, req.Roster)
+		}
+	}
+
+	// Keep the roster so the game can be rerun with the same players
+	inputs.PlayerIDs = make([]uint, 0, len(group.Players))
+	for _, p := range group.Players {
+		inputs.PlayerIDs = append(inputs.PlayerIDs, p.ID)
+	}
+
 	if len(group.Players) < req.NumTeams {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "not enough players for the requested number of teams"})
 		return
 	}
 
-	// Generate teams
-	teams, err := teamgen.GenerateBalancedTeams(group.Players, req.NumTeams, req.LockedPlayers, req.SeparatedPlayers)
+	// Use this group's skill weights where they differ from the players' global weights
+	if err := applySkillOverrides(db, group.ID, group.Players); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill weights"})
+		return
+	}
+
+	scale, err := groupSkillScale(db, group)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill scale"})
+		return
+	}
+
+	// Generate teams, balancing on learned ratings if the group has opted in
+	weights := balancingWeights(group, group.Players, scale)
+	teams, err := teamgen.GenerateBalancedTeamsWithWeights(group.Players, weights, req.NumTeams, req.LockedPlayers, req.SeparatedPlayers)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Rotate the teams through the ice when asked to
+	var rot *rotation.Rotation
+	var rotationJSON []byte
+	if req.PeriodMinutes > 0 {
+		minutes, err := rotationSessionMinutes(db, req.SessionID, req.SessionMinutes)
+		if errors.Is(err, errNoSessionLength) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load session"})
+			return
+		}
+		r, err := rotation.Schedule(req.NumTeams, minutes, req.PeriodMinutes)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if rotationJSON, err = json.Marshal(r); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save game"})
+			return
+		}
+		rot = &r
+	}
+
 	// Generate share ID for the game
 	shareID, err := utils.GenerateShareID(10)
 	if err != nil {
@@ -271,8 +796,20 @@ func (h *GroupHandler) GenerateTeams(c *gin.Context) {
 		return
 	}
 
-	// Marshal teams data to JSON
-	teamsJSON, err := json.Marshal(teams)
+	// Store a snapshot of the teams in the versioned teams data format
+	stored := make([]gamedata.Team, 0, len(teams))
+	for _, team := range teams {
+		stored = append(stored, teamData(team, weights))
+	}
+	teamsJSON, err := gamedata.Encode(stored)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save game"})
+		return
+	}
+	// A rerun can only reproduce the original's balancing if the algorithm hasn't changed since
+	algorithmChanged := inputs.AlgorithmVersion != teamgen.AlgorithmVersion
+	inputs.AlgorithmVersion = teamgen.AlgorithmVersion
+	generationJSON, err := json.Marshal(inputs)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save game"})
 		return
@@ -281,26 +818,38 @@ func (h *GroupHandler) GenerateTeams(c *gin.Context) {
 	// Save game to database
 	game := models.Game{
 		ShareID:         shareID,
-		UserID:          userID,
-		GroupID:         uint(groupID),
+		UserID:          group.UserID,
+		GroupID:         group.ID,
+		OrganizationID:  group.OrganizationID, // Games belong to the group's organization so its ratings include them
+		SessionID:       req.SessionID,
 		GroupName:       group.Name,
 		GroupLogo:       group.Logo,       // Copy logo for public access
 		LogoContentType: group.LogoContentType,
 		NumTeams:        req.NumTeams,
 		UseJerseyColors: req.UseJerseyColors,
 		TeamsData:       teamsJSON,
+		RotationData:    rotationJSON,
+		GenerationData:  generationJSON,
 		CreatedAt:       time.Now(),
 	}
+	for _, team := range stored {
+		game.Teams = append(game.Teams, models.NewGameTeam(shareID, team))
+	}
 
-	if err := h.db.Create(&game).Error; err != nil {
+	if err := db.Create(&game).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save game"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"teams":    teams,
 		"share_id": shareID,
-	})
+		"rotation": rot,
+	}
+	if inputs.RerunOf != "" {
+		response["algorithm_changed"] = algorithmChanged
+	}
+	c.JSON(http.StatusOK, response)
 }
 
 // GetGame retrieves a game by share ID (public endpoint, no auth required)
@@ -308,14 +857,21 @@ func (h *GroupHandler) GetGame(c *gin.Context) {
 	shareID := c.Param("shareId")
 
 	var game models.Game
-	if err := h.db.Where("share_id = ?", shareID).First(&game).Error; err != nil {
+	if err := preloadGameTeams(h.db).Where("share_id = ?", shareID).First(&game).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
 		return
 	}
 
-	// Unmarshal teams data
-	var teams []teamgen.Team
-	if err := json.Unmarshal(game.TeamsData, &teams); err != nil {
+	var rot *rotation.Rotation
+	if len(game.RotationData) > 0 {
+		if err := json.Unmarshal(game.RotationData, &rot); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load game data"})
+			return
+		}
+	}
+
+	var scores []models.GameScore
+	if err := h.db.Where("game_share_id = ?", game.ShareID).Order("team_number").Find(&scores).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load game data"})
 		return
 	}
@@ -325,7 +881,10 @@ func (h *GroupHandler) GetGame(c *gin.Context) {
 		"group_name":        game.GroupName,
 		"num_teams":         game.NumTeams,
 		"use_jersey_colors": game.UseJerseyColors,
-		"teams":             teams,
+		"teams":             game.Teams,
+		"revision":          game.Revision,
+		"rotation":          rot,
+		"scores":            scores,
 		"created_at":        game.CreatedAt,
 		"has_logo":          len(game.GroupLogo) > 0,
 	})
@@ -333,17 +892,8 @@ func (h *GroupHandler) GetGame(c *gin.Context) {
 
 // UploadGroupLogo handles logo upload for a group
 func (h *GroupHandler) UploadGroupLogo(c *gin.Context) {
-	userID := auth.GetUserID(c)
-	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
-		return
-	}
-
-	// Verify group belongs to user
-	var group models.Group
-	if err := h.db.Where("id = ? AND user_id = ?", groupID, userID).First(&group).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
 		return
 	}
 
@@ -435,17 +985,8 @@ func (h *GroupHandler) GetGameLogo(c *gin.Context) {
 
 // DeleteGroupLogo deletes a group's logo
 func (h *GroupHandler) DeleteGroupLogo(c *gin.Context) {
-	userID := auth.GetUserID(c)
-	groupID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group ID"})
-		return
-	}
-
-	// Verify group belongs to user
-	var group models.Group
-	if err := h.db.Where("id = ? AND user_id = ?", groupID, userID).First(&group).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "group not found"})
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
 		return
 	}
 
@@ -460,3 +1001,40 @@ func (h *GroupHandler) DeleteGroupLogo(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "logo deleted successfully"})
 }
+
+// skillOverrides returns the per-group skill weights of a group, keyed by player ID
+func skillOverrides(db *gorm.DB, groupID uint) (map[uint]float64, error) {
+	var memberships []models.GroupPlayer
+	if err := db.Where("group_id = ? AND skill_weight IS NOT NULL", groupID).Find(&memberships).Error; err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[uint]float64, len(memberships))
+	for _, m := range memberships {
+		overrides[m.PlayerID] = *m.SkillWeight
+	}
+	return overrides, nil
+}
+
+// applySkillOverrides replaces each player's SkillWeight with the group's override, if any
+func applySkillOverrides(db *gorm.DB, groupID uint, players []models.Player) error {
+	overrides, err := skillOverrides(db, groupID)
+	if err != nil {
+		return err
+	}
+
+	for i := range players {
+		if w, ok := overrides[players[i].ID]; ok {
+			players[i].SkillWeight = w
+		}
+	}
+	return nil
+}
+
+// sameWeight compares two optional skill weights
+func sameWeight(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
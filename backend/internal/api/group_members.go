@@ -0,0 +1,339 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/auth"
+	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	errInviteWrongEmail = errors.New("invite was sent to a different email")
+	errAlreadyMember    = errors.New("already a member of this group")
+)
+
+type GroupInviteRequest struct {
+	Email string `json:"email" binding:"omitempty,email"` // Optional, only this user can accept
+	Role  string `json:"role" binding:"required,oneof=admin viewer"`
+}
+
+type SetMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin viewer"`
+}
+
+type TransferOwnershipRequest struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
+
+// GetMembers returns the users with access to a group and their roles
+func (h *GroupHandler) GetMembers(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var members []models.GroupMember
+	if err := h.db.Preload("User").Where("group_id = ?", group.ID).Order("created_at ASC").Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch members"})
+		return
+	}
+
+	result := make([]gin.H, 0, len(members))
+	for _, m := range members {
+		result = append(result, gin.H{
+			"user_id":    m.UserID,
+			"email":      m.User.Email,
+			"role":       m.Role,
+			"created_at": m.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SetMemberRole changes a member's role. Ownership is changed with TransferOwnership instead.
+func (h *GroupHandler) SetMemberRole(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req SetMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var member models.GroupMember
+	if err := h.db.Where("group_id = ? AND user_id = ?", group.ID, memberID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+		return
+	}
+	if member.Role == models.GroupRoleOwner {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "transfer ownership to change the owner's role"})
+		return
+	}
+
+	if err := h.db.Model(&member).Where("group_id = ? AND user_id = ?", group.ID, memberID).Update("role", req.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update member"})
+		return
+	}
+	member.Role = req.Role
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember removes a user's access to a group. The owner can remove anyone else, and any
+// other member can remove themselves.
+func (h *GroupHandler) RemoveMember(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if uint(memberID) != userID && group.Role != models.GroupRoleOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient group permissions"})
+		return
+	}
+
+	var member models.GroupMember
+	if err := h.db.Where("group_id = ? AND user_id = ?", group.ID, memberID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+		return
+	}
+	if member.Role == models.GroupRoleOwner {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "the owner can't be removed, transfer ownership first"})
+		return
+	}
+
+	if err := h.db.Where("group_id = ? AND user_id = ?", group.ID, memberID).Delete(&models.GroupMember{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+}
+
+// GetGroupInvites returns a group's invites that haven't been accepted yet
+func (h *GroupHandler) GetGroupInvites(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	var invites []models.GroupInvite
+	if err := h.db.Where("group_id = ? AND accepted_at IS NULL", group.ID).Order("created_at ASC").Find(&invites).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch invites"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invites)
+}
+
+// CreateGroupInvite creates an invite link to join a group with a role
+func (h *GroupHandler) CreateGroupInvite(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	var req GroupInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	token, err := utils.GenerateShareID(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate invite"})
+		return
+	}
+
+	invite := models.GroupInvite{
+		GroupID:         group.ID,
+		Email:           strings.ToLower(req.Email),
+		Role:            req.Role,
+		Token:           token,
+		CreatedByUserID: userID,
+		ExpiresAt:       time.Now().Add(inviteTTL),
+	}
+
+	if err := h.db.Create(&invite).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create invite"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invite)
+}
+
+// DeleteGroupInvite revokes an invite
+func (h *GroupHandler) DeleteGroupInvite(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	inviteID, err := strconv.ParseUint(c.Param("invite_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invite ID"})
+		return
+	}
+
+	result := h.db.Where("id = ? AND group_id = ? AND accepted_at IS NULL", inviteID, group.ID).Delete(&models.GroupInvite{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete invite"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invite not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invite deleted"})
+}
+
+// AcceptGroupInvite adds the authenticated user to the group an invite was created for
+func (h *GroupHandler) AcceptGroupInvite(c *gin.Context) {
+	userID := auth.GetUserID(c)
+	token := c.Param("token")
+
+	var member models.GroupMember
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var invite models.GroupInvite
+		if err := tx.Where("token = ?", token).First(&invite).Error; err != nil {
+			return errInviteNotFound
+		}
+		if invite.AcceptedAt != nil {
+			return errInviteUsed
+		}
+		if time.Now().After(invite.ExpiresAt) {
+			return errInviteExpired
+		}
+
+		if invite.Email != "" {
+			var user models.User
+			if err := tx.First(&user, userID).Error; err != nil {
+				return err
+			}
+			if !strings.EqualFold(user.Email, invite.Email) {
+				return errInviteWrongEmail
+			}
+		}
+
+		var count int64
+		if err := tx.Model(&models.GroupMember{}).Where("group_id = ? AND user_id = ?", invite.GroupID, userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errAlreadyMember
+		}
+
+		if err := tx.Model(&invite).Update("accepted_at", time.Now()).Error; err != nil {
+			return err
+		}
+		member = models.GroupMember{GroupID: invite.GroupID, UserID: userID, Role: invite.Role}
+		return tx.Create(&member).Error
+	})
+	switch {
+	case errors.Is(err, errInviteNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, errInviteWrongEmail):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, errInviteUsed), errors.Is(err, errInviteExpired), errors.Is(err, errAlreadyMember):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to accept invite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// TransferOwnership makes another member the group's owner. The previous owner stays on as
+// an admin.
+func (h *GroupHandler) TransferOwnership(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	var req TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	if req.UserID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "you already own this group"})
+		return
+	}
+
+	var count int64
+	if err := h.db.Model(&models.GroupMember{}).Where("group_id = ? AND user_id = ?", group.ID, req.UserID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to transfer ownership"})
+		return
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "new owner must already be a member"})
+		return
+	}
+
+	// Keep the group on the previous owner's skill scale so existing weights stay valid
+	if group.SkillScaleID == nil {
+		var owner models.User
+		if err := h.db.Select("id", "skill_scale_id").First(&owner, group.UserID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to transfer ownership"})
+			return
+		}
+		group.SkillScaleID = owner.SkillScaleID
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.GroupMember{}).Where("group_id = ? AND user_id = ?", group.ID, userID).
+			Update("role", models.GroupRoleAdmin).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.GroupMember{}).Where("group_id = ? AND user_id = ?", group.ID, req.UserID).
+			Update("role", models.GroupRoleOwner).Error; err != nil {
+			return err
+		}
+		return tx.Model(&group).Updates(map[string]interface{}{
+			"user_id":        req.UserID,
+			"skill_scale_id": group.SkillScaleID,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to transfer ownership"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "ownership transferred"})
+}
@@ -0,0 +1,315 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/auth"
+	"github.com/sticktoss/backend/internal/ledger"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type LedgerHandler struct {
+	db *gorm.DB
+}
+
+func NewLedgerHandler(db *gorm.DB) *LedgerHandler {
+	return &LedgerHandler{db: db}
+}
+
+type PaymentRequest struct {
+	PlayerID    uint   `json:"player_id" binding:"required"`
+	AmountCents int    `json:"amount_cents" binding:"required,min=1"`
+	SessionID   *uint  `json:"session_id"` // Optional session the payment was made at
+	Note        string `json:"note" binding:"max=255"`
+}
+
+// PlayerBalance is what a player has been charged and has paid in a group. A positive
+// balance is owed to the group.
+type PlayerBalance struct {
+	PlayerID     uint   `json:"player_id"`
+	Name         string `json:"name"`
+	ChargedCents int    `json:"charged_cents"`
+	PaidCents    int    `json:"paid_cents"`
+	BalanceCents int    `json:"balance_cents"`
+}
+
+// ChargeSession splits a session's cost between the players who came, replacing any earlier
+// charges for it. Players who checked in are charged, or those who RSVP'd in if no one has
+// checked in yet. The group's rules decide who pays less: spares pay the group's spare share,
+// goalies can be free, and regulars can be charged even when they miss the session.
+func (h *SessionHandler) ChargeSession(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+	if session.CostCents == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session has no cost"})
+		return
+	}
+
+	var group models.Group
+	if err := h.db.Preload("Players").First(&group, session.GroupID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load group"})
+		return
+	}
+	var memberships []models.GroupPlayer
+	if err := h.db.Where("group_id = ?", group.ID).Find(&memberships).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load players"})
+		return
+	}
+	byMember := make(map[uint]models.GroupPlayer, len(memberships))
+	for _, m := range memberships {
+		byMember[m.PlayerID] = m
+	}
+	byPlayer, err := sessionAttendance(h.db, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load attendance"})
+		return
+	}
+
+	checkedIn := false
+	for _, a := range byPlayer {
+		if a.CheckedInAt != nil {
+			checkedIn = true
+		}
+	}
+
+	var payers []models.Player
+	var shares []float64
+	for _, p := range group.Players {
+		a, ok := byPlayer[p.ID]
+		came := ok && (checkedIn && a.CheckedInAt != nil || !checkedIn && a.Status == models.RSVPIn)
+		membership := byMember[p.ID]
+		if !came && !(group.RegularsAlwaysPay && membership.IsRegular) {
+			continue
+		}
+
+		share := 1.0
+		switch {
+		case group.GoaliesFree && p.Position == models.PositionGoalie:
+			share = 0
+		case membership.IsSpare:
+			share = group.SpareShare
+		}
+		if share > 0 {
+			payers = append(payers, p)
+			shares = append(shares, share)
+		}
+	}
+	if len(payers) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no one to charge for this session"})
+		return
+	}
+
+	userID := auth.GetUserID(c)
+	sessionID := session.ID
+	amounts := ledger.Split(session.CostCents, shares)
+	charges := make([]models.LedgerEntry, 0, len(payers))
+	for i, p := range payers {
+		charges = append(charges, models.LedgerEntry{
+			GroupID:          group.ID,
+			PlayerID:         p.ID,
+			SessionID:        &sessionID,
+			Kind:             models.LedgerCharge,
+			AmountCents:      amounts[i],
+			RecordedByUserID: userID,
+		})
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("session_id = ? AND kind = ?", session.ID, models.LedgerCharge).
+			Delete(&models.LedgerEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&charges).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to charge session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": session.ID,
+		"cost_cents": session.CostCents,
+		"charges":    charges,
+	})
+}
+
+// GetLedger returns a group's ledger entries, newest first, optionally for one player or
+// session
+func (h *LedgerHandler) GetLedger(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	query := h.db.Where("group_id = ?", group.ID)
+	for _, param := range []string{"player_id", "session_id"} {
+		if value := c.Query(param); value != "" {
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param})
+				return
+			}
+			query = query.Where(param+" = ?", id)
+		}
+	}
+
+	var entries []models.LedgerEntry
+	if err := query.Order("created_at DESC, id DESC").Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch ledger"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// RecordPayment records money a player paid towards their balance
+func (h *LedgerHandler) RecordPayment(c *gin.Context) {
+	var req PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var count int64
+	if err := h.db.Model(&models.GroupPlayer{}).Where("group_id = ? AND player_id = ?", group.ID, req.PlayerID).
+		Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record payment"})
+		return
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not found in group"})
+		return
+	}
+	if req.SessionID != nil {
+		if err := h.db.Where("id = ? AND group_id = ?", *req.SessionID, group.ID).First(&models.Session{}).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			return
+		}
+	}
+
+	entry := models.LedgerEntry{
+		GroupID:          group.ID,
+		PlayerID:         req.PlayerID,
+		SessionID:        req.SessionID,
+		Kind:             models.LedgerPayment,
+		AmountCents:      req.AmountCents,
+		Note:             req.Note,
+		RecordedByUserID: auth.GetUserID(c),
+	}
+	if err := h.db.Create(&entry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record payment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// DeleteLedgerEntry removes a charge or payment recorded by mistake
+func (h *LedgerHandler) DeleteLedgerEntry(c *gin.Context) {
+	entryID, err := strconv.ParseUint(c.Param("entry_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid entry ID"})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	result := h.db.Where("id = ? AND group_id = ?", entryID, group.ID).Delete(&models.LedgerEntry{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete entry"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "entry deleted"})
+}
+
+// GetBalances returns each of a group's players' charges, payments and balance, largest
+// balance owed first. Players who have left the group are included while they have entries,
+// without a name if they have since been deleted.
+func (h *LedgerHandler) GetBalances(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db.Preload("Players"), models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var totals []struct {
+		PlayerID uint
+		Kind     string
+		Total    int
+	}
+	if err := h.db.Model(&models.LedgerEntry{}).Select("player_id, kind, SUM(amount_cents) AS total").
+		Where("group_id = ?", group.ID).Group("player_id, kind").Scan(&totals).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch balances"})
+		return
+	}
+
+	balances := make(map[uint]*PlayerBalance)
+	for _, p := range group.Players {
+		balances[p.ID] = &PlayerBalance{PlayerID: p.ID, Name: p.Name}
+	}
+	var former []uint
+	for _, t := range totals {
+		b, ok := balances[t.PlayerID]
+		if !ok {
+			b = &PlayerBalance{PlayerID: t.PlayerID}
+			balances[t.PlayerID] = b
+			former = append(former, t.PlayerID)
+		}
+		if t.Kind == models.LedgerCharge {
+			b.ChargedCents += t.Total
+		} else {
+			b.PaidCents += t.Total
+		}
+	}
+	if len(former) > 0 {
+		var players []models.Player
+		if err := h.db.Where("id IN ?", former).Find(&players).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch balances"})
+			return
+		}
+		for _, p := range players {
+			balances[p.ID].Name = p.Name
+		}
+	}
+
+	result := make([]PlayerBalance, 0, len(balances))
+	for _, b := range balances {
+		b.BalanceCents = b.ChargedCents - b.PaidCents
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].BalanceCents != result[j].BalanceCents {
+			return result[i].BalanceCents > result[j].BalanceCents
+		}
+		return result[i].PlayerID < result[j].PlayerID
+	})
+
+	c.JSON(http.StatusOK, result)
+}
+
+// unsettledGroups returns the groups whose ledgers say a player still owes or is owed money
+func unsettledGroups(db *gorm.DB, playerID uint) ([]uint, error) {
+	var groupIDs []uint
+	err := db.Model(&models.LedgerEntry{}).Where("player_id = ?", playerID).Group("group_id").
+		Having("SUM(CASE WHEN kind = ? THEN amount_cents ELSE -amount_cents END) <> 0", models.LedgerCharge).
+		Pluck("group_id", &groupIDs).Error
+	return groupIDs, err
+}
@@ -0,0 +1,310 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/auth"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	errInviteNotFound = errors.New("invite not found")
+	errInviteUsed     = errors.New("invite has already been used")
+	errInviteExpired  = errors.New("invite has expired")
+	errPlayerClaimed  = errors.New("player has already been claimed")
+)
+
+// MeHandler serves the self-service endpoints for users who have claimed a player. These
+// users can RSVP, view their games and edit their name, but not their skill weight.
+type MeHandler struct {
+	db *gorm.DB
+}
+
+func NewMeHandler(db *gorm.DB) *MeHandler {
+	return &MeHandler{db: db}
+}
+
+type UpdateMyPlayerRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// AcceptInvite links the player an invite was created for to the authenticated user
+func (h *MeHandler) AcceptInvite(c *gin.Context) {
+	userID := auth.GetUserID(c)
+	token := c.Param("token")
+
+	var player models.Player
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var invite models.PlayerInvite
+		if err := tx.Where("token = ?", token).First(&invite).Error; err != nil {
+			return errInviteNotFound
+		}
+		if invite.AcceptedAt != nil {
+			return errInviteUsed
+		}
+		if time.Now().After(invite.ExpiresAt) {
+			return errInviteExpired
+		}
+
+		if err := tx.First(&player, invite.PlayerID).Error; err != nil {
+			return errInviteNotFound
+		}
+		if player.ClaimedByUserID != nil {
+			return errPlayerClaimed
+		}
+
+		now := time.Now()
+		if err := tx.Model(&invite).Update("accepted_at", now).Error; err != nil {
+			return err
+		}
+		player.ClaimedByUserID = &userID
+		return tx.Model(&player).Update("claimed_by_user_id", userID).Error
+	})
+	switch {
+	case errors.Is(err, errInviteNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, errInviteUsed), errors.Is(err, errInviteExpired), errors.Is(err, errPlayerClaimed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to accept invite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, myPlayer(player))
+}
+
+// GetMyPlayers returns the players the authenticated user has claimed
+func (h *MeHandler) GetMyPlayers(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	var players []models.Player
+	if err := h.db.Preload("Groups").Where("claimed_by_user_id = ?", userID).Find(&players).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch players"})
+		return
+	}
+
+	result := make([]gin.H, 0, len(players))
+	for _, p := range players {
+		groups := make([]gin.H, 0, len(p.Groups))
+		for _, g := range p.Groups {
+			groups = append(groups, gin.H{"id": g.ID, "name": g.Name})
+		}
+		entry := myPlayer(p)
+		entry["groups"] = groups
+		result = append(result, entry)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateMyPlayer lets a user edit their own player's profile
+func (h *MeHandler) UpdateMyPlayer(c *gin.Context) {
+	player, ok := h.findMyPlayer(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateMyPlayerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	player.Name = req.Name
+	if err := h.db.Model(&player).Update("name", player.Name).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update player"})
+		return
+	}
+
+	c.JSON(http.StatusOK, myPlayer(player))
+}
+
+// GetMySessions returns upcoming sessions in the player's groups with the player's RSVP
+func (h *MeHandler) GetMySessions(c *gin.Context) {
+	player, ok := h.findMyPlayer(c)
+	if !ok {
+		return
+	}
+
+	groupIDs, err := playerGroupIDs(h.db, player.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch sessions"})
+		return
+	}
+
+	var sessions []models.Session
+	if err := h.db.Preload("Group").Where("group_id IN ? AND ends_at >= ?", groupIDs, time.Now()).
+		Order("starts_at ASC").Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch sessions"})
+		return
+	}
+
+	var records []models.Attendance
+	if err := h.db.Where("player_id = ?", player.ID).Find(&records).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch sessions"})
+		return
+	}
+	bySession := make(map[uint]models.Attendance, len(records))
+	for _, a := range records {
+		bySession[a.SessionID] = a
+	}
+
+	result := make([]gin.H, 0, len(sessions))
+	for _, s := range sessions {
+		entry := gin.H{
+			"session":       s,
+			"group_name":    s.Group.Name,
+			"status":        "",
+			"checked_in_at": nil,
+		}
+		if a, ok := bySession[s.ID]; ok {
+			entry["status"] = a.Status
+			entry["checked_in_at"] = a.CheckedInAt
+		}
+		result = append(result, entry)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SetMyRSVP records the player's RSVP for a session in one of their groups. RSVPs close when
+// the session starts, so a no-show can't be changed to out afterwards; admins can still
+// correct attendance.
+func (h *MeHandler) SetMyRSVP(c *gin.Context) {
+	player, ok := h.findMyPlayer(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return
+	}
+
+	var req RSVPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	groupIDs, err := playerGroupIDs(h.db, player.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update RSVP"})
+		return
+	}
+
+	var session models.Session
+	if err := h.db.Where("id = ? AND group_id IN ?", sessionID, groupIDs).First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+	if !session.StartsAt.After(time.Now()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "session has already started"})
+		return
+	}
+
+	var attendance models.Attendance
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		attendance, err = applyRSVP(tx, session, player.ID, req.Status, time.Now())
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update RSVP"})
+		return
+	}
+
+	c.JSON(http.StatusOK, attendance)
+}
+
+// GetMyGames returns the games the player was on a team in, newest first
+func (h *MeHandler) GetMyGames(c *gin.Context) {
+	player, ok := h.findMyPlayer(c)
+	if !ok {
+		return
+	}
+
+	groupIDs, err := playerGroupIDs(h.db, player.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch games"})
+		return
+	}
+
+	var games []models.Game
+	if err := preloadGameTeams(h.db).Preload("Scores").Omit("group_logo").Where("group_id IN ?", groupIDs).
+		Order("created_at DESC").Find(&games).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch games"})
+		return
+	}
+
+	result := make([]gin.H, 0)
+	for _, g := range games {
+		teamNumber := 0
+		for _, t := range gameLineup(g) {
+			for _, p := range t.Players {
+				if p.ID == player.ID {
+					teamNumber = t.Number
+				}
+			}
+		}
+		if teamNumber == 0 {
+			continue
+		}
+
+		result = append(result, gin.H{
+			"share_id":           g.ShareID,
+			"group_id":           g.GroupID,
+			"group_name":         g.GroupName,
+			"session_id":         g.SessionID,
+			"team_number":        teamNumber,
+			"scores":             g.Scores,
+			"result_recorded_at": g.ResultRecordedAt,
+			"created_at":         g.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// findMyPlayer loads the player from the :id param, verifying the authenticated user has
+// claimed it. It writes the error response and returns false if not.
+func (h *MeHandler) findMyPlayer(c *gin.Context) (models.Player, bool) {
+	userID := auth.GetUserID(c)
+	playerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		return models.Player{}, false
+	}
+
+	var player models.Player
+	if err := h.db.Where("id = ? AND claimed_by_user_id = ?", playerID, userID).First(&player).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+		return models.Player{}, false
+	}
+
+	return player, true
+}
+
+// playerGroupIDs returns the IDs of the groups a player belongs to
+func playerGroupIDs(db *gorm.DB, playerID uint) ([]uint, error) {
+	var groupIDs []uint
+	err := db.Model(&models.GroupPlayer{}).Where("player_id = ?", playerID).Pluck("group_id", &groupIDs).Error
+	return groupIDs, err
+}
+
+// myPlayer is the view of a player shown to the user who claimed it, without the skill
+// weight the organizer assigned
+func myPlayer(p models.Player) gin.H {
+	return gin.H{
+		"id":   p.ID,
+		"name": p.Name,
+	}
+}
@@ -0,0 +1,331 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/auth"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type OrganizationHandler struct {
+	db *gorm.DB
+}
+
+func NewOrganizationHandler(db *gorm.DB) *OrganizationHandler {
+	return &OrganizationHandler{db: db}
+}
+
+type OrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type AddOrganizationMemberRequest struct {
+	Email string `json:"email" binding:"required,email"` // Must belong to an existing user
+	Role  string `json:"role" binding:"required,oneof=owner admin viewer"`
+}
+
+type SetOrganizationMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner admin viewer"`
+}
+
+// GetOrganizations returns the organizations the authenticated user belongs to
+func (h *OrganizationHandler) GetOrganizations(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	var members []models.OrganizationMember
+	if err := h.db.Where("user_id = ?", userID).Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch organizations"})
+		return
+	}
+	roles := make(map[uint]string, len(members))
+	orgIDs := make([]uint, 0, len(members))
+	for _, m := range members {
+		roles[m.OrganizationID] = m.Role
+		orgIDs = append(orgIDs, m.OrganizationID)
+	}
+
+	var orgs []models.Organization
+	if err := h.db.Where("id IN ?", orgIDs).Order("personal DESC, name ASC").Find(&orgs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch organizations"})
+		return
+	}
+	for i := range orgs {
+		orgs[i].Role = roles[orgs[i].ID]
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+// GetOrganization returns a single organization by ID
+func (h *OrganizationHandler) GetOrganization(c *gin.Context) {
+	org, ok := authorizeOrganization(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// CreateOrganization creates a new organization owned by the authenticated user
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	var req OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org := models.Organization{Name: req.Name}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&org).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.OrganizationMember{OrganizationID: org.ID, UserID: userID, Role: models.GroupRoleOwner}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create organization"})
+		return
+	}
+	org.Role = models.GroupRoleOwner
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// UpdateOrganization renames an organization
+func (h *OrganizationHandler) UpdateOrganization(c *gin.Context) {
+	var req OrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org, ok := authorizeOrganization(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	org.Name = req.Name
+	if err := h.db.Save(&org).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update organization"})
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// DeleteOrganization deletes an organization. It must not own any players or groups, and
+// personal organizations can't be deleted.
+func (h *OrganizationHandler) DeleteOrganization(c *gin.Context) {
+	org, ok := authorizeOrganization(c, h.db, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	if org.Personal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "personal organizations can't be deleted"})
+		return
+	}
+
+	var players, groups int64
+	if err := h.db.Model(&models.Player{}).Where("organization_id = ?", org.ID).Count(&players).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete organization"})
+		return
+	}
+	if err := h.db.Model(&models.Group{}).Where("organization_id = ?", org.ID).Count(&groups).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete organization"})
+		return
+	}
+	if players > 0 || groups > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "organization still owns players or groups"})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ?", org.ID).Delete(&models.OrganizationMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&org).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete organization"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "organization deleted"})
+}
+
+// GetOrganizationMembers returns the users in an organization and their roles
+func (h *OrganizationHandler) GetOrganizationMembers(c *gin.Context) {
+	org, ok := authorizeOrganization(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var members []models.OrganizationMember
+	if err := h.db.Preload("User").Where("organization_id = ?", org.ID).Order("created_at ASC").Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch members"})
+		return
+	}
+
+	result := make([]gin.H, 0, len(members))
+	for _, m := range members {
+		result = append(result, gin.H{
+			"user_id":    m.UserID,
+			"email":      m.User.Email,
+			"role":       m.Role,
+			"created_at": m.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// AddOrganizationMember adds an existing user to an organization by email
+func (h *OrganizationHandler) AddOrganizationMember(c *gin.Context) {
+	var req AddOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	org, ok := authorizeOrganization(c, h.db, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	if org.Personal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "personal organizations can't have other members"})
+		return
+	}
+
+	var user models.User
+	if err := h.db.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
+		return
+	}
+
+	var count int64
+	if err := h.db.Model(&models.OrganizationMember{}).Where("organization_id = ? AND user_id = ?", org.ID, user.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add member"})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "already a member of this organization"})
+		return
+	}
+
+	member := models.OrganizationMember{OrganizationID: org.ID, UserID: user.ID, Role: req.Role}
+	if err := h.db.Create(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to add member"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// SetOrganizationMemberRole changes a member's role. An organization always keeps at least
+// one owner.
+func (h *OrganizationHandler) SetOrganizationMemberRole(c *gin.Context) {
+	org, ok := authorizeOrganization(c, h.db, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	var req SetOrganizationMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var member models.OrganizationMember
+	if err := h.db.Where("organization_id = ? AND user_id = ?", org.ID, memberID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+		return
+	}
+	if member.Role == models.GroupRoleOwner && req.Role != models.GroupRoleOwner {
+		last, err := h.lastOwner(org.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update member"})
+			return
+		}
+		if last {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "an organization must keep at least one owner"})
+			return
+		}
+	}
+
+	if err := h.db.Model(&member).Where("organization_id = ? AND user_id = ?", org.ID, memberID).Update("role", req.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update member"})
+		return
+	}
+	member.Role = req.Role
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveOrganizationMember removes a user from an organization. Owners can remove anyone,
+// and any other member can remove themselves. The last owner can't be removed.
+func (h *OrganizationHandler) RemoveOrganizationMember(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	org, ok := authorizeOrganization(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	if uint(memberID) != userID && org.Role != models.GroupRoleOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient organization permissions"})
+		return
+	}
+
+	var member models.OrganizationMember
+	if err := h.db.Where("organization_id = ? AND user_id = ?", org.ID, memberID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "member not found"})
+		return
+	}
+	if member.Role == models.GroupRoleOwner {
+		last, err := h.lastOwner(org.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove member"})
+			return
+		}
+		if last {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "an organization must keep at least one owner"})
+			return
+		}
+	}
+
+	if err := h.db.Where("organization_id = ? AND user_id = ?", org.ID, memberID).Delete(&models.OrganizationMember{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+}
+
+// lastOwner reports whether an organization has only one owner left
+func (h *OrganizationHandler) lastOwner(orgID uint) (bool, error) {
+	var owners int64
+	if err := h.db.Model(&models.OrganizationMember{}).Where("organization_id = ? AND role = ?", orgID, models.GroupRoleOwner).
+		Count(&owners).Error; err != nil {
+		return false, err
+	}
+	return owners <= 1, nil
+}
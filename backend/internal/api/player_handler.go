@@ -3,13 +3,18 @@ package api
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sticktoss/backend/internal/auth"
 	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/utils"
 	"gorm.io/gorm"
 )
 
+// inviteTTL is how long a player invite can be accepted for
+const inviteTTL = 7 * 24 * time.Hour
+
 type PlayerHandler struct {
 	db *gorm.DB
 }
@@ -19,21 +24,35 @@ func NewPlayerHandler(db *gorm.DB) *PlayerHandler {
 }
 
 type CreatePlayerRequest struct {
-	Name        string `json:"name" binding:"required"`
-	SkillWeight int    `json:"skill_weight" binding:"required,min=1,max=5"`
+	Name           string   `json:"name" binding:"required"`
+	SkillWeight    *float64 `json:"skill_weight" binding:"required"`                           // Must be on the account's skill scale
+	OrganizationID *uint    `json:"organization_id"`                                           // Optional, defaults to the user's personal organization
+	Position       string   `json:"position" binding:"omitempty,oneof=forward defense goalie"` // Optional
 }
 
 type UpdatePlayerRequest struct {
-	Name        string `json:"name"`
-	SkillWeight int    `json:"skill_weight" binding:"omitempty,min=1,max=5"`
+	Name        string   `json:"name"`
+	SkillWeight *float64 `json:"skill_weight"`                                              // Must be on the account's skill scale
+	Position    string   `json:"position" binding:"omitempty,oneof=forward defense goalie"` // Optional, keeps the current position when empty
 }
 
-// GetPlayers returns all players for the authenticated user
+// GetPlayers returns all players the authenticated user can see: their own, those owned by
+// their organizations and those in groups they belong to
 func (h *PlayerHandler) GetPlayers(c *gin.Context) {
 	userID := auth.GetUserID(c)
 
+	// Include players from groups the user co-organizes
+	sharedGroups := h.db.Model(&models.GroupMember{}).Select("group_id").Where("user_id = ?", userID)
+	sharedPlayers := h.db.Model(&models.GroupPlayer{}).Select("player_id").Where("group_id IN (?)", sharedGroups)
+	orgs := h.db.Model(&models.OrganizationMember{}).Select("organization_id").Where("user_id = ?", userID)
+
+	query := h.db.Where("user_id = ? OR id IN (?) OR organization_id IN (?)", userID, sharedPlayers, orgs)
+	if orgID := c.Query("organization_id"); orgID != "" {
+		query = query.Where("organization_id = ?", orgID)
+	}
+
 	var players []models.Player
-	if err := h.db.Where("user_id = ?", userID).Find(&players).Error; err != nil {
+	if err := query.Find(&players).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch players"})
 		return
 	}
@@ -43,20 +62,66 @@ func (h *PlayerHandler) GetPlayers(c *gin.Context) {
 
 // GetPlayer returns a single player by ID
 func (h *PlayerHandler) GetPlayer(c *gin.Context) {
-	userID := auth.GetUserID(c)
-	playerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	player, ok := authorizePlayer(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, player)
+}
+
+// GetPlayerRatings returns a player's learned rating and its history, one entry per recorded game
+func (h *PlayerHandler) GetPlayerRatings(c *gin.Context) {
+	player, ok := authorizePlayer(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	scale, err := accountSkillScale(h.db, player.UserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill scale"})
 		return
 	}
 
-	var player models.Player
-	if err := h.db.Where("id = ? AND user_id = ?", playerID, userID).First(&player).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+	var history []models.PlayerRating
+	if err := h.db.Where("player_id = ?", player.ID).Order("played_at ASC").Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch rating history"})
 		return
 	}
 
-	c.JSON(http.StatusOK, player)
+	c.JSON(http.StatusOK, gin.H{
+		"player_id":    player.ID,
+		"rating":       effectiveRating(player, scale),
+		"games_played": len(history),
+		"history":      history,
+	})
+}
+
+// GetWeightHistory returns every recorded change to a player's skill weight, including
+// their per-group weights
+func (h *PlayerHandler) GetWeightHistory(c *gin.Context) {
+	player, ok := authorizePlayer(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	query := h.db.Where("player_id = ?", player.ID)
+	if value := c.Query("group_id"); value != "" {
+		groupID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid group_id"})
+			return
+		}
+		query = query.Where("group_id = ?", groupID)
+	}
+
+	var changes []models.SkillWeightChange
+	if err := query.Order("created_at ASC, id ASC").Find(&changes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch weight history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
 }
 
 // CreatePlayer creates a new player
@@ -69,13 +134,36 @@ func (h *PlayerHandler) CreatePlayer(c *gin.Context) {
 		return
 	}
 
+	orgID, ok := targetOrganization(c, h.db, req.OrganizationID)
+	if !ok {
+		return
+	}
+
+	scale, err := accountSkillScale(h.db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill scale"})
+		return
+	}
+	if !scale.Contains(*req.SkillWeight) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "skill weight is not on the skill scale"})
+		return
+	}
+
 	player := models.Player{
-		UserID:      userID,
-		Name:        req.Name,
-		SkillWeight: req.SkillWeight,
+		UserID:         userID,
+		OrganizationID: orgID,
+		Name:           req.Name,
+		SkillWeight:    *req.SkillWeight,
+		Position:       req.Position,
 	}
 
-	if err := h.db.Create(&player).Error; err != nil {
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&player).Error; err != nil {
+			return err
+		}
+		return recordWeightChange(tx, player.ID, nil, nil, &player.SkillWeight, userID, models.WeightSourceManual)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create player"})
 		return
 	}
@@ -86,15 +174,9 @@ func (h *PlayerHandler) CreatePlayer(c *gin.Context) {
 // UpdatePlayer updates an existing player
 func (h *PlayerHandler) UpdatePlayer(c *gin.Context) {
 	userID := auth.GetUserID(c)
-	playerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
-		return
-	}
 
-	var player models.Player
-	if err := h.db.Where("id = ? AND user_id = ?", playerID, userID).First(&player).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+	player, ok := authorizePlayer(c, h.db, models.GroupRoleAdmin)
+	if !ok {
 		return
 	}
 
@@ -104,14 +186,37 @@ func (h *PlayerHandler) UpdatePlayer(c *gin.Context) {
 		return
 	}
 
+	oldWeight := player.SkillWeight
 	if req.Name != "" {
 		player.Name = req.Name
 	}
-	if req.SkillWeight > 0 {
-		player.SkillWeight = req.SkillWeight
+	if req.Position != "" {
+		player.Position = req.Position
+	}
+	if req.SkillWeight != nil {
+		// Weights are on the scale of the account the player belongs to
+		scale, err := accountSkillScale(h.db, player.UserID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill scale"})
+			return
+		}
+		if !scale.Contains(*req.SkillWeight) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "skill weight is not on the skill scale"})
+			return
+		}
+		player.SkillWeight = *req.SkillWeight
 	}
 
-	if err := h.db.Save(&player).Error; err != nil {
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&player).Error; err != nil {
+			return err
+		}
+		if player.SkillWeight == oldWeight {
+			return nil
+		}
+		return recordWeightChange(tx, player.ID, nil, &oldWeight, &player.SkillWeight, userID, models.WeightSourceManual)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update player"})
 		return
 	}
@@ -119,26 +224,117 @@ func (h *PlayerHandler) UpdatePlayer(c *gin.Context) {
 	c.JSON(http.StatusOK, player)
 }
 
-// DeletePlayer deletes a player
+// DeletePlayer deletes a player. Their ledger entries are kept so group balances still add
+// up, and players who owe or are owed money can't be deleted until they're settled.
 func (h *PlayerHandler) DeletePlayer(c *gin.Context) {
-	userID := auth.GetUserID(c)
-	playerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	player, ok := authorizePlayer(c, h.db, models.GroupRoleOwner)
+	if !ok {
+		return
+	}
+
+	unsettled, err := unsettledGroups(h.db, player.ID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete player"})
+		return
+	}
+	if len(unsettled) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "player has an unsettled ledger balance; settle it first"})
 		return
 	}
 
-	var player models.Player
-	if err := h.db.Where("id = ? AND user_id = ?", playerID, userID).First(&player).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "player not found"})
+	groupIDs, err := playerGroupIDs(h.db, player.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete player"})
 		return
 	}
 
-	// Delete player (this will also remove from groups due to foreign key constraints)
-	if err := h.db.Delete(&player).Error; err != nil {
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := withdrawPlayer(tx, player.ID, groupIDs, time.Now()); err != nil {
+			return err
+		}
+		if err := tx.Where("player_id = ?", player.ID).Delete(&models.PlayerInvite{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("player_id = ?", player.ID).Delete(&models.SeasonTeamPlayer{}).Error; err != nil {
+			return err
+		}
+		// Nothing cascades from players, so their group memberships go explicitly
+		if err := tx.Where("player_id = ?", player.ID).Delete(&models.GroupPlayer{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&player).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete player"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "player deleted"})
 }
+
+// CreateInvite creates an invite link that lets someone claim the player with their own account
+func (h *PlayerHandler) CreateInvite(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	player, ok := authorizePlayer(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	if player.ClaimedByUserID != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "player has already been claimed"})
+		return
+	}
+
+	token, err := utils.GenerateShareID(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate invite"})
+		return
+	}
+
+	invite := models.PlayerInvite{
+		PlayerID:        player.ID,
+		Token:           token,
+		CreatedByUserID: userID,
+		ExpiresAt:       time.Now().Add(inviteTTL),
+	}
+
+	if err := h.db.Create(&invite).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create invite"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invite)
+}
+
+// Unclaim unlinks a player from the account that claimed it
+func (h *PlayerHandler) Unclaim(c *gin.Context) {
+	player, ok := authorizePlayer(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	if player.ClaimedByUserID == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player has not been claimed"})
+		return
+	}
+
+	if err := h.db.Model(&player).Update("claimed_by_user_id", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unclaim player"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "player unclaimed"})
+}
+
+// recordWeightChange adds an entry to a player's skill weight history
+func recordWeightChange(tx *gorm.DB, playerID uint, groupID *uint, oldWeight, newWeight *float64, userID uint, source string) error {
+	return tx.Create(&models.SkillWeightChange{
+		PlayerID:        playerID,
+		GroupID:         groupID,
+		OldWeight:       oldWeight,
+		NewWeight:       newWeight,
+		ChangedByUserID: userID,
+		Source:          source,
+	}).Error
+}
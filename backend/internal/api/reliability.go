@@ -0,0 +1,161 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// minPenaltySessions is how many tracked sessions a player must have RSVPed in to before the
+// no-show penalty can apply, so one missed game doesn't send a new player to the back
+const minPenaltySessions = 3
+
+// PlayerReliability summarizes how a player's RSVPs compare to their actual attendance. Only
+// sessions that have ended and used check-in count towards attendance and no-shows.
+type PlayerReliability struct {
+	PlayerID      uint     `json:"player_id"`
+	Name          string   `json:"name"`
+	RSVPedIn      int      `json:"rsvped_in"`      // Tracked sessions the player was in for
+	Attended      int      `json:"attended"`       // Tracked sessions the player checked in to, including walk-ins
+	NoShows       int      `json:"no_shows"`       // Tracked sessions the player was in for but didn't check in to
+	LateCancels   int      `json:"late_cancels"`   // Sessions the player dropped out of within the group's late cancel window
+	NoShowRate    *float64 `json:"no_show_rate"`   // No-shows per session RSVPed in to, nil until there is one
+	CurrentStreak int      `json:"current_streak"` // Sessions attended in a row up to the latest, broken by a no-show or late cancel
+	LongestStreak int      `json:"longest_streak"`
+	Penalized     bool     `json:"penalized"` // Moved to the back of waitlists by the group's no-show penalty
+}
+
+// GetReliability returns attendance reliability statistics for each of a group's players
+func (h *SessionHandler) GetReliability(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db.Preload("Players"), models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	stats, err := reliabilityStats(h.db, group, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch reliability"})
+		return
+	}
+
+	result := make([]PlayerReliability, 0, len(group.Players))
+	for _, p := range group.Players {
+		r := PlayerReliability{PlayerID: p.ID}
+		if s, ok := stats[p.ID]; ok {
+			r = *s
+		}
+		r.Name = p.Name
+		r.Penalized = penalized(group, r)
+		result = append(result, r)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"late_cancel_hours":    group.LateCancelHours,
+		"no_show_penalty_rate": group.NoShowPenaltyRate,
+		"players":              result,
+	})
+}
+
+// reliabilityStats works out every player's reliability across a group's sessions that ended
+// before now. Sessions where nobody checked in aren't tracked, since attendance wasn't taken.
+func reliabilityStats(tx *gorm.DB, group models.Group, now time.Time) (map[uint]*PlayerReliability, error) {
+	var sessions []models.Session
+	if err := tx.Where("group_id = ? AND ends_at <= ?", group.ID, now).Order("starts_at ASC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return map[uint]*PlayerReliability{}, nil
+	}
+	sessionIDs := make([]uint, 0, len(sessions))
+	for _, s := range sessions {
+		sessionIDs = append(sessionIDs, s.ID)
+	}
+
+	var attendances []models.Attendance
+	if err := tx.Where("session_id IN ?", sessionIDs).Find(&attendances).Error; err != nil {
+		return nil, err
+	}
+	bySession := make(map[uint][]models.Attendance)
+	for _, a := range attendances {
+		bySession[a.SessionID] = append(bySession[a.SessionID], a)
+	}
+
+	var drops []models.SessionEvent
+	if err := tx.Where("session_id IN ? AND type = ?", sessionIDs, models.SessionEventDropped).Find(&drops).Error; err != nil {
+		return nil, err
+	}
+	type key struct{ sessionID, playerID uint }
+	lastDrop := make(map[key]time.Time)
+	for _, e := range drops {
+		k := key{e.SessionID, e.PlayerID}
+		if e.CreatedAt.After(lastDrop[k]) {
+			lastDrop[k] = e.CreatedAt
+		}
+	}
+
+	window := time.Duration(group.LateCancelHours) * time.Hour
+	stats := make(map[uint]*PlayerReliability)
+	get := func(playerID uint) *PlayerReliability {
+		if stats[playerID] == nil {
+			stats[playerID] = &PlayerReliability{PlayerID: playerID}
+		}
+		return stats[playerID]
+	}
+
+	for _, session := range sessions {
+		tracked := false
+		for _, a := range bySession[session.ID] {
+			if a.CheckedInAt != nil {
+				tracked = true
+				break
+			}
+		}
+
+		for _, a := range bySession[session.ID] {
+			switch {
+			case a.CheckedInAt != nil:
+				s := get(a.PlayerID)
+				if a.Status == models.RSVPIn {
+					s.RSVPedIn++
+				}
+				s.Attended++
+				s.CurrentStreak++
+				s.LongestStreak = max(s.LongestStreak, s.CurrentStreak)
+			case a.Status == models.RSVPIn && tracked:
+				s := get(a.PlayerID)
+				s.RSVPedIn++
+				s.NoShows++
+				s.CurrentStreak = 0
+			case a.Status != models.RSVPIn:
+				// Dropping out and staying out close to the start is a late cancel
+				dropped, ok := lastDrop[key{session.ID, a.PlayerID}]
+				if ok && !dropped.Before(session.StartsAt.Add(-window)) {
+					s := get(a.PlayerID)
+					s.LateCancels++
+					s.CurrentStreak = 0
+				}
+			}
+		}
+	}
+
+	for _, s := range stats {
+		if s.RSVPedIn > 0 {
+			rate := float64(s.NoShows) / float64(s.RSVPedIn)
+			s.NoShowRate = &rate
+		}
+	}
+	return stats, nil
+}
+
+// penalized reports whether the group's no-show penalty applies to a player
+func penalized(group models.Group, r PlayerReliability) bool {
+	return group.NoShowPenaltyRate > 0 && r.NoShowRate != nil &&
+		r.RSVPedIn >= minPenaltySessions && *r.NoShowRate >= group.NoShowPenaltyRate
+}
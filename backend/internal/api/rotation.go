@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/rotation"
+	"gorm.io/gorm"
+)
+
+var errNoSessionLength = errors.New("session_minutes is required when the game isn't for a session")
+
+type RotationRequest struct {
+	SessionMinutes int `json:"session_minutes" binding:"omitempty,min=1"` // Optional, defaults to the length of the game's session
+	PeriodMinutes  int `json:"period_minutes" binding:"required,min=1"`
+}
+
+// SetRotation builds the on-ice rotation for a game with three or more teams, replacing any
+// existing one
+func (h *GameHandler) SetRotation(c *gin.Context) {
+	game, ok := h.findGame(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req RotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	minutes, err := rotationSessionMinutes(h.db, game.SessionID, req.SessionMinutes)
+	if errors.Is(err, errNoSessionLength) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load session"})
+		return
+	}
+
+	r, err := rotation.Schedule(game.NumTeams, minutes, req.PeriodMinutes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save rotation"})
+		return
+	}
+
+	if err := h.db.Model(&game).Update("rotation_data", data).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save rotation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, r)
+}
+
+// rotationSessionMinutes returns the session length to build a rotation for: the given
+// length, or by default the length of the game's session
+func rotationSessionMinutes(db *gorm.DB, sessionID *uint, given int) (int, error) {
+	if given > 0 {
+		return given, nil
+	}
+	if sessionID == nil {
+		return 0, errNoSessionLength
+	}
+	var session models.Session
+	if err := db.First(&session, *sessionID).Error; err != nil {
+		return 0, err
+	}
+	return int(session.EndsAt.Sub(session.StartsAt).Minutes()), nil
+}
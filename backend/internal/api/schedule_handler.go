@@ -0,0 +1,530 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/schedule"
+	"gorm.io/gorm"
+)
+
+const (
+	// materializeWeeks is how far ahead sessions are created from schedules
+	materializeWeeks = 4
+	// maxOccurrences caps how many occurrences can be listed at once
+	maxOccurrences = 100
+)
+
+var (
+	errInvalidUntil    = errors.New("invalid until date, expected YYYY-MM-DD")
+	errInvalidTimezone = errors.New("invalid timezone")
+)
+
+type ScheduleHandler struct {
+	db *gorm.DB
+}
+
+func NewScheduleHandler(db *gorm.DB) *ScheduleHandler {
+	return &ScheduleHandler{db: db}
+}
+
+type ScheduleRequest struct {
+	Frequency       string   `json:"frequency" binding:"required,oneof=weekly biweekly custom"`
+	ByDay           []string `json:"by_day"` // Day codes (MO, TU, ...), defaults to the start date's weekday
+	RRule           string   `json:"rrule"`  // Required for custom frequency, e.g. "FREQ=WEEKLY;INTERVAL=3;BYDAY=TU"
+	Until           string   `json:"until"`  // Optional last date (YYYY-MM-DD)
+	StartDate       string   `json:"start_date" binding:"required"`
+	StartTime       string   `json:"start_time" binding:"required"`
+	DurationMinutes int      `json:"duration_minutes" binding:"required,min=1"`
+	Timezone        string   `json:"timezone" binding:"required"`
+	Venue           string   `json:"venue"`
+	Capacity        int      `json:"capacity" binding:"min=0"`
+	CostCents       int      `json:"cost_cents" binding:"min=0"` // Ice cost of each session
+}
+
+type ScheduleExceptionRequest struct {
+	Date string `json:"date" binding:"required"` // YYYY-MM-DD
+}
+
+// GetSchedules returns a group's recurring schedules
+func (h *ScheduleHandler) GetSchedules(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var schedules []models.SessionSchedule
+	if err := h.db.Preload("Exceptions").Where("group_id = ?", group.ID).Find(&schedules).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch schedules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// GetSchedule returns a single schedule
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	sched, ok := h.findSchedule(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, sched)
+}
+
+// CreateSchedule creates a recurring schedule and materializes its upcoming sessions
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	sched := models.SessionSchedule{GroupID: group.ID}
+	if err := applyScheduleRequest(&sched, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&sched).Error; err != nil {
+			return err
+		}
+		return materializeSchedule(tx, sched, time.Now(), true)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create schedule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sched)
+}
+
+// UpdateSchedule changes a schedule and re-syncs its upcoming sessions. Upcoming sessions
+// that no longer occur are removed; past sessions are left alone.
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	sched, ok := h.findSchedule(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := applyScheduleRequest(&sched, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Exceptions").Save(&sched).Error; err != nil {
+			return err
+		}
+		return materializeSchedule(tx, sched, time.Now(), true)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sched)
+}
+
+// DeleteSchedule deletes a schedule along with its upcoming sessions. Past sessions are kept
+// as one-off sessions.
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	sched, ok := h.findSchedule(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var upcoming []models.Session
+		if err := tx.Where("schedule_id = ? AND starts_at >= ?", sched.ID, now).Find(&upcoming).Error; err != nil {
+			return err
+		}
+		for _, session := range upcoming {
+			if err := deleteSession(tx, session); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.Session{}).Where("schedule_id = ?", sched.ID).
+			Updates(map[string]interface{}{"schedule_id": nil, "occurrence_date": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("schedule_id = ?", sched.ID).Delete(&models.SessionScheduleException{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&sched).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "schedule deleted"})
+}
+
+// GetOccurrences lists the next occurrences of a schedule, with the session materialized
+// for each one if there is one
+func (h *ScheduleHandler) GetOccurrences(c *gin.Context) {
+	sched, ok := h.findSchedule(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	count := 10
+	if countStr := c.Query("count"); countStr != "" {
+		n, err := strconv.Atoi(countStr)
+		if err != nil || n < 1 || n > maxOccurrences {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "count must be between 1 and 100"})
+			return
+		}
+		count = n
+	}
+
+	s, err := toSchedule(sched)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid schedule"})
+		return
+	}
+
+	occurrences, err := s.Occurrences(time.Now(), count)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid schedule"})
+		return
+	}
+
+	var sessions []models.Session
+	if err := h.db.Where("schedule_id = ?", sched.ID).Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch sessions"})
+		return
+	}
+	sessionByDate := make(map[string]uint, len(sessions))
+	for _, session := range sessions {
+		sessionByDate[session.OccurrenceDate] = session.ID
+	}
+
+	type occurrence struct {
+		schedule.Occurrence
+		SessionID *uint `json:"session_id"`
+	}
+	result := make([]occurrence, 0, len(occurrences))
+	for _, o := range occurrences {
+		occ := occurrence{Occurrence: o}
+		if id, ok := sessionByDate[o.Date]; ok {
+			occ.SessionID = &id
+		}
+		result = append(result, occ)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// AddScheduleException cancels one date of a schedule, removing its session if it was
+// already materialized
+func (h *ScheduleHandler) AddScheduleException(c *gin.Context) {
+	sched, ok := h.findSchedule(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req ScheduleExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := time.Parse(schedule.DateLayout, req.Date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date, expected YYYY-MM-DD"})
+		return
+	}
+
+	exception := models.SessionScheduleException{ScheduleID: sched.ID, Date: req.Date}
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.FirstOrCreate(&exception, exception).Error; err != nil {
+			return err
+		}
+
+		var sessions []models.Session
+		if err := tx.Where("schedule_id = ? AND occurrence_date = ?", sched.ID, req.Date).Find(&sessions).Error; err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			if err := deleteSession(tx, session); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel date"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, exception)
+}
+
+// DeleteScheduleException restores a cancelled date
+func (h *ScheduleHandler) DeleteScheduleException(c *gin.Context) {
+	sched, ok := h.findSchedule(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	date := c.Param("date")
+	result := h.db.Where("schedule_id = ? AND date = ?", sched.ID, date).Delete(&models.SessionScheduleException{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore date"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "exception not found"})
+		return
+	}
+
+	if err := h.db.Preload("Exceptions").First(&sched, sched.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore date"})
+		return
+	}
+	if err := materializeSchedule(h.db, sched, time.Now(), false); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to restore date"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "date restored"})
+}
+
+// findSchedule loads the schedule from the :id and :schedule_id params, verifying the
+// authenticated user has at least minRole in the group. It writes the error response and
+// returns false if not.
+func (h *ScheduleHandler) findSchedule(c *gin.Context, minRole string) (models.SessionSchedule, bool) {
+	scheduleID, err := strconv.ParseUint(c.Param("schedule_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule ID"})
+		return models.SessionSchedule{}, false
+	}
+
+	group, ok := authorizeGroup(c, h.db, minRole)
+	if !ok {
+		return models.SessionSchedule{}, false
+	}
+
+	var sched models.SessionSchedule
+	if err := h.db.Preload("Exceptions").Where("id = ? AND group_id = ?", scheduleID, group.ID).First(&sched).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "schedule not found"})
+		return models.SessionSchedule{}, false
+	}
+
+	return sched, true
+}
+
+// applyScheduleRequest validates a schedule request and copies it onto the model
+func applyScheduleRequest(sched *models.SessionSchedule, req ScheduleRequest) error {
+	var rule schedule.Rule
+	switch req.Frequency {
+	case "weekly", "biweekly":
+		rule.Interval = 1
+		if req.Frequency == "biweekly" {
+			rule.Interval = 2
+		}
+		byDay := strings.ToUpper(strings.Join(req.ByDay, ","))
+		if byDay != "" {
+			parsed, err := schedule.ParseRule("FREQ=WEEKLY;BYDAY=" + byDay)
+			if err != nil {
+				return err
+			}
+			rule.ByDay = parsed.ByDay
+		}
+	case "custom":
+		parsed, err := schedule.ParseRule(req.RRule)
+		if err != nil {
+			return err
+		}
+		rule = parsed
+	}
+	if req.Until != "" {
+		if _, err := time.Parse(schedule.DateLayout, req.Until); err != nil {
+			return errInvalidUntil
+		}
+		rule.Until = req.Until
+	}
+
+	sched.Frequency = req.Frequency
+	sched.Rule = rule.String()
+	sched.StartDate = req.StartDate
+	sched.StartTime = req.StartTime
+	sched.DurationMinutes = req.DurationMinutes
+	sched.Timezone = req.Timezone
+	sched.Venue = req.Venue
+	sched.Capacity = req.Capacity
+	sched.CostCents = req.CostCents
+
+	// Computing an occurrence validates the dates, time and timezone
+	s, err := toSchedule(*sched)
+	if err != nil {
+		return err
+	}
+	_, err = s.Occurrences(time.Time{}, 1)
+	return err
+}
+
+// toSchedule converts a stored schedule into its recurrence form
+func toSchedule(sched models.SessionSchedule) (schedule.Schedule, error) {
+	loc, err := time.LoadLocation(sched.Timezone)
+	if err != nil {
+		return schedule.Schedule{}, errInvalidTimezone
+	}
+
+	rule, err := schedule.ParseRule(sched.Rule)
+	if err != nil {
+		return schedule.Schedule{}, err
+	}
+
+	exceptions := make(map[string]bool, len(sched.Exceptions))
+	for _, e := range sched.Exceptions {
+		exceptions[e.Date] = true
+	}
+
+	return schedule.Schedule{
+		Rule:       rule,
+		StartDate:  sched.StartDate,
+		StartTime:  sched.StartTime,
+		Duration:   time.Duration(sched.DurationMinutes) * time.Minute,
+		Location:   loc,
+		Exceptions: exceptions,
+	}, nil
+}
+
+// materializeSchedule creates sessions for a schedule's occurrences over the next few
+// weeks. With resync, upcoming sessions are also updated to match the schedule and those
+// that no longer occur are removed; otherwise existing sessions are left as edited.
+func materializeSchedule(tx *gorm.DB, sched models.SessionSchedule, now time.Time, resync bool) error {
+	s, err := toSchedule(sched)
+	if err != nil {
+		return err
+	}
+
+	occurrences, err := s.Between(now, now.AddDate(0, 0, 7*materializeWeeks))
+	if err != nil {
+		return err
+	}
+
+	var existing []models.Session
+	if err := tx.Where("schedule_id = ? AND starts_at >= ?", sched.ID, now).Find(&existing).Error; err != nil {
+		return err
+	}
+	existingByDate := make(map[string]models.Session, len(existing))
+	for _, session := range existing {
+		existingByDate[session.OccurrenceDate] = session
+	}
+
+	occurs := make(map[string]bool, len(occurrences))
+	for _, o := range occurrences {
+		occurs[o.Date] = true
+
+		session, ok := existingByDate[o.Date]
+		if ok && !resync {
+			continue
+		}
+		if !ok {
+			// Sessions that have already started aren't in existing but may still be stored
+			var count int64
+			if err := tx.Model(&models.Session{}).Where("schedule_id = ? AND occurrence_date = ?", sched.ID, o.Date).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+		}
+
+		scheduleID := sched.ID
+		session.GroupID = sched.GroupID
+		session.ScheduleID = &scheduleID
+		session.OccurrenceDate = o.Date
+		session.StartsAt = o.StartsAt
+		session.EndsAt = o.EndsAt
+		session.Venue = sched.Venue
+		session.Capacity = sched.Capacity
+		session.CostCents = sched.CostCents
+		if err := tx.Save(&session).Error; err != nil {
+			return err
+		}
+		if ok {
+			if err := promoteWaitlist(tx, session); err != nil {
+				return err
+			}
+		}
+	}
+
+	if resync {
+		for date, session := range existingByDate {
+			if !occurs[date] {
+				if err := deleteSession(tx, session); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// MaterializeSchedules tops up the upcoming sessions of every schedule now and then once
+// every interval, so sessions keep appearing as time passes. It runs until the server stops.
+func MaterializeSchedules(db *gorm.DB, interval time.Duration) {
+	for {
+		materializeAllSchedules(db, time.Now())
+		time.Sleep(interval)
+	}
+}
+
+// materializeAllSchedules tops up the upcoming sessions of every schedule, logging the
+// schedules that fail so one bad schedule doesn't hold up the rest
+func materializeAllSchedules(db *gorm.DB, now time.Time) {
+	var schedules []models.SessionSchedule
+	err := db.Preload("Exceptions").FindInBatches(&schedules, 100, func(_ *gorm.DB, _ int) error {
+		for _, sched := range schedules {
+			if err := db.Transaction(func(tx *gorm.DB) error {
+				return materializeSchedule(tx, sched, now, false)
+			}); err != nil {
+				log.Printf("Failed to create sessions for schedule %d: %v", sched.ID, err)
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		log.Printf("Failed to load schedules: %v", err)
+	}
+}
+
+// materializeGroupSchedules tops up the upcoming sessions of all of a group's schedules
+func materializeGroupSchedules(tx *gorm.DB, groupID uint) error {
+	var schedules []models.SessionSchedule
+	if err := tx.Preload("Exceptions").Where("group_id = ?", groupID).Find(&schedules).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, sched := range schedules {
+		if err := materializeSchedule(tx, sched, now, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
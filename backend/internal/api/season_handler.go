@@ -0,0 +1,436 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/league"
+	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/teamgen"
+	"gorm.io/gorm"
+)
+
+type SeasonHandler struct {
+	db *gorm.DB
+}
+
+func NewSeasonHandler(db *gorm.DB) *SeasonHandler {
+	return &SeasonHandler{db: db}
+}
+
+type CreateSeasonRequest struct {
+	Name             string   `json:"name" binding:"required"`
+	NumTeams         int      `json:"num_teams" binding:"required,min=2"`
+	TeamNames        []string `json:"team_names"`                            // Optional, defaults to "Team 1", "Team 2", ...
+	LockedPlayers    [][]uint `json:"locked_players"`                        // Players that must be drafted onto the same team
+	SeparatedPlayers [][]uint `json:"separated_players"`                     // Players that must be drafted onto different teams
+	PointsWin        *int     `json:"points_win" binding:"omitempty,min=0"`  // Optional, defaults to 2
+	PointsTie        *int     `json:"points_tie" binding:"omitempty,min=0"`  // Optional, defaults to 1
+	PointsLoss       *int     `json:"points_loss" binding:"omitempty,min=0"` // Optional, defaults to 0
+}
+
+type ScheduleSeasonRequest struct {
+	Legs       int    `json:"legs" binding:"omitempty,min=1,max=4"` // Times each pair of teams meets, defaults to 1
+	SessionIDs []uint `json:"session_ids"`                          // Optional, defaults to the group's upcoming sessions
+}
+
+type MatchResultRequest struct {
+	HomeScore *int `json:"home_score" binding:"required,min=0"`
+	AwayScore *int `json:"away_score" binding:"required,min=0"`
+}
+
+// TeamStanding is a season team's row in the standings
+type TeamStanding struct {
+	TeamID uint   `json:"team_id"`
+	Name   string `json:"name"`
+	league.Standing
+}
+
+// GetSeasons returns a group's seasons
+func (h *SeasonHandler) GetSeasons(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var seasons []models.Season
+	if err := h.db.Where("group_id = ?", group.ID).Order("created_at DESC").Find(&seasons).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch seasons"})
+		return
+	}
+
+	c.JSON(http.StatusOK, seasons)
+}
+
+// GetSeason returns a season with its teams and schedule
+func (h *SeasonHandler) GetSeason(c *gin.Context) {
+	season, ok := h.findSeason(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Preload("Teams", func(db *gorm.DB) *gorm.DB {
+		return db.Order("number ASC")
+	}).Preload("Teams.Players").Preload("Matches", func(db *gorm.DB) *gorm.DB {
+		return db.Order("round ASC, id ASC")
+	}).First(&season, season.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch season"})
+		return
+	}
+
+	c.JSON(http.StatusOK, season)
+}
+
+// CreateSeason starts a season, drafting the group's players onto balanced fixed teams
+func (h *SeasonHandler) CreateSeason(c *gin.Context) {
+	var req CreateSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.TeamNames) > 0 && len(req.TeamNames) != req.NumTeams {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "team_names must have one name per team"})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db.Preload("Players"), models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	// Spares aren't drafted, they fill in when someone can't make it
+	spares, err := spareIDs(h.db, group.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load players"})
+		return
+	}
+	players := make([]models.Player, 0, len(group.Players))
+	for _, p := range group.Players {
+		if !spares[p.ID] {
+			players = append(players, p)
+		}
+	}
+	if len(players) < req.NumTeams {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "not enough players for the requested number of teams"})
+		return
+	}
+
+	if err := applySkillOverrides(h.db, group.ID, players); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill weights"})
+		return
+	}
+	scale, err := groupSkillScale(h.db, group)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load skill scale"})
+		return
+	}
+
+	weights := balancingWeights(group, players, scale)
+	teams, err := teamgen.GenerateBalancedTeamsWithWeights(players, weights, req.NumTeams, req.LockedPlayers, req.SeparatedPlayers)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	season := models.Season{
+		GroupID:    group.ID,
+		Name:       req.Name,
+		NumTeams:   req.NumTeams,
+		PointsWin:  2,
+		PointsTie:  1,
+		PointsLoss: 0,
+	}
+	if req.PointsWin != nil {
+		season.PointsWin = *req.PointsWin
+	}
+	if req.PointsTie != nil {
+		season.PointsTie = *req.PointsTie
+	}
+	if req.PointsLoss != nil {
+		season.PointsLoss = *req.PointsLoss
+	}
+
+	for _, team := range teams {
+		name := fmt.Sprintf("Team %d", team.Number)
+		if len(req.TeamNames) > 0 {
+			name = req.TeamNames[team.Number-1]
+		}
+		season.Teams = append(season.Teams, models.SeasonTeam{
+			Number:  team.Number,
+			Name:    name,
+			Players: team.Players,
+		})
+	}
+
+	if err := h.db.Create(&season).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create season"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, season)
+}
+
+// DeleteSeason deletes a season with its teams and schedule
+func (h *SeasonHandler) DeleteSeason(c *gin.Context) {
+	season, ok := h.findSeason(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		return deleteSeasons(tx, []uint{season.ID})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete season"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "season deleted"})
+}
+
+// ScheduleSeason generates a round-robin schedule for a season, one round per session.
+// Rounds beyond the available sessions are left without one. Any existing schedule is
+// replaced, so this is refused once results have been recorded.
+func (h *SeasonHandler) ScheduleSeason(c *gin.Context) {
+	var req ScheduleSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Legs == 0 {
+		req.Legs = 1
+	}
+
+	season, ok := h.findSeason(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var played int64
+	if err := h.db.Model(&models.SeasonMatch{}).Where("season_id = ? AND result_recorded_at IS NOT NULL", season.ID).Count(&played).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to schedule season"})
+		return
+	}
+	if played > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "season already has results"})
+		return
+	}
+
+	var teams []models.SeasonTeam
+	if err := h.db.Where("season_id = ?", season.ID).Find(&teams).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to schedule season"})
+		return
+	}
+	teamIDs := make(map[int]uint, len(teams))
+	for _, t := range teams {
+		teamIDs[t.Number] = t.ID
+	}
+
+	sessions, ok := h.seasonSessions(c, season, req.SessionIDs)
+	if !ok {
+		return
+	}
+
+	matches := []models.SeasonMatch{}
+	for i, round := range league.RoundRobin(season.NumTeams, req.Legs) {
+		var sessionID *uint
+		if i < len(sessions) {
+			sessionID = &sessions[i].ID
+		}
+		for _, p := range round {
+			matches = append(matches, models.SeasonMatch{
+				SeasonID:   season.ID,
+				Round:      i + 1,
+				SessionID:  sessionID,
+				HomeTeamID: teamIDs[p.Home],
+				AwayTeamID: teamIDs[p.Away],
+			})
+		}
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("season_id = ?", season.ID).Delete(&models.SeasonMatch{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&matches).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to schedule season"})
+		return
+	}
+
+	c.JSON(http.StatusOK, matches)
+}
+
+// RecordMatchResult records or corrects the final score of a season match
+func (h *SeasonHandler) RecordMatchResult(c *gin.Context) {
+	var req MatchResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	season, ok := h.findSeason(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	matchID, err := strconv.ParseUint(c.Param("match_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	var match models.SeasonMatch
+	if err := h.db.Where("id = ? AND season_id = ?", matchID, season.ID).First(&match).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "match not found"})
+		return
+	}
+
+	now := time.Now()
+	match.HomeScore = req.HomeScore
+	match.AwayScore = req.AwayScore
+	match.ResultRecordedAt = &now
+
+	if err := h.db.Save(&match).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record result"})
+		return
+	}
+
+	c.JSON(http.StatusOK, match)
+}
+
+// GetStandings returns a season's standings table from its recorded results
+func (h *SeasonHandler) GetStandings(c *gin.Context) {
+	season, ok := h.findSeason(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var teams []models.SeasonTeam
+	if err := h.db.Where("season_id = ?", season.ID).Find(&teams).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch standings"})
+		return
+	}
+	byNumber := make(map[int]models.SeasonTeam, len(teams))
+	numbers := make(map[uint]int, len(teams))
+	for _, t := range teams {
+		byNumber[t.Number] = t
+		numbers[t.ID] = t.Number
+	}
+
+	var matches []models.SeasonMatch
+	if err := h.db.Where("season_id = ? AND result_recorded_at IS NOT NULL", season.ID).Find(&matches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch standings"})
+		return
+	}
+
+	results := make([]league.Result, 0, len(matches))
+	for _, m := range matches {
+		if m.HomeScore == nil || m.AwayScore == nil {
+			continue
+		}
+		results = append(results, league.Result{
+			Home:      numbers[m.HomeTeamID],
+			Away:      numbers[m.AwayTeamID],
+			HomeScore: *m.HomeScore,
+			AwayScore: *m.AwayScore,
+		})
+	}
+
+	points := league.Points{Win: season.PointsWin, Tie: season.PointsTie, Loss: season.PointsLoss}
+	table := league.Standings(season.NumTeams, results, points)
+
+	standings := make([]TeamStanding, 0, len(table))
+	for _, s := range table {
+		team := byNumber[s.Team]
+		standings = append(standings, TeamStanding{TeamID: team.ID, Name: team.Name, Standing: s})
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
+// findSeason loads the season from the :id and :season_id params, verifying the
+// authenticated user has at least minRole in the group. It writes the error response and
+// returns false if not.
+func (h *SeasonHandler) findSeason(c *gin.Context, minRole string) (models.Season, bool) {
+	seasonID, err := strconv.ParseUint(c.Param("season_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid season ID"})
+		return models.Season{}, false
+	}
+
+	group, ok := authorizeGroup(c, h.db, minRole)
+	if !ok {
+		return models.Season{}, false
+	}
+
+	var season models.Season
+	if err := h.db.Where("id = ? AND group_id = ?", seasonID, group.ID).First(&season).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "season not found"})
+		return models.Season{}, false
+	}
+
+	return season, true
+}
+
+// seasonSessions returns the sessions to schedule a season's rounds in, in order: the given
+// sessions, or by default the group's upcoming ones. It writes the error response and
+// returns false if a given session isn't in the group.
+func (h *SeasonHandler) seasonSessions(c *gin.Context, season models.Season, sessionIDs []uint) ([]models.Session, bool) {
+	var sessions []models.Session
+
+	if len(sessionIDs) == 0 {
+		if err := materializeGroupSchedules(h.db, season.GroupID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch sessions"})
+			return nil, false
+		}
+		if err := h.db.Where("group_id = ? AND starts_at >= ?", season.GroupID, time.Now()).
+			Order("starts_at ASC").Find(&sessions).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch sessions"})
+			return nil, false
+		}
+		return sessions, true
+	}
+
+	if err := h.db.Where("id IN ? AND group_id = ?", sessionIDs, season.GroupID).Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch sessions"})
+		return nil, false
+	}
+	byID := make(map[uint]models.Session, len(sessions))
+	for _, s := range sessions {
+		byID[s.ID] = s
+	}
+
+	ordered := make([]models.Session, 0, len(sessionIDs))
+	for _, id := range sessionIDs {
+		s, ok := byID[id]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("session %d not found", id)})
+			return nil, false
+		}
+		ordered = append(ordered, s)
+	}
+	return ordered, true
+}
+
+// deleteSeasons deletes seasons with their teams and matches
+func deleteSeasons(tx *gorm.DB, seasonIDs []uint) error {
+	if len(seasonIDs) == 0 {
+		return nil
+	}
+	teams := tx.Model(&models.SeasonTeam{}).Select("id").Where("season_id IN ?", seasonIDs)
+	if err := tx.Where("season_team_id IN (?)", teams).Delete(&models.SeasonTeamPlayer{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("season_id IN ?", seasonIDs).Delete(&models.SeasonTeam{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("season_id IN ?", seasonIDs).Delete(&models.SeasonMatch{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", seasonIDs).Delete(&models.Season{}).Error
+}
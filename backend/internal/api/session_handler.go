@@ -0,0 +1,242 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type SessionHandler struct {
+	db *gorm.DB
+}
+
+func NewSessionHandler(db *gorm.DB) *SessionHandler {
+	return &SessionHandler{db: db}
+}
+
+type SessionRequest struct {
+	StartsAt  time.Time `json:"starts_at" binding:"required"`
+	EndsAt    time.Time `json:"ends_at" binding:"required"`
+	Venue     string    `json:"venue"`
+	Capacity  int       `json:"capacity" binding:"min=0"`             // 0 = unlimited
+	CostCents *int      `json:"cost_cents" binding:"omitempty,min=0"` // Optional, keeps the current cost when updating
+}
+
+// GetSessions returns a group's sessions, optionally limited to a date range
+func (h *SessionHandler) GetSessions(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	query := h.db.Where("group_id = ?", group.ID)
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date"})
+			return
+		}
+		query = query.Where("starts_at >= ?", t)
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date"})
+			return
+		}
+		query = query.Where("starts_at < ?", t)
+	}
+
+	var sessions []models.Session
+	if err := query.Order("starts_at ASC").Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// GetSession returns a single session with the games generated for it and attendance counts
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var games []models.Game
+	if err := h.db.Select("share_id", "num_teams", "result_recorded_at", "created_at").
+		Where("session_id = ?", session.ID).Order("created_at ASC").Find(&games).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch games"})
+		return
+	}
+
+	gameSummaries := make([]gin.H, 0, len(games))
+	for _, g := range games {
+		gameSummaries = append(gameSummaries, gin.H{
+			"share_id":           g.ShareID,
+			"num_teams":          g.NumTeams,
+			"result_recorded_at": g.ResultRecordedAt,
+			"created_at":         g.CreatedAt,
+		})
+	}
+
+	var group models.Group
+	if err := h.db.Preload("Players").First(&group, session.GroupID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch attendance"})
+		return
+	}
+	byPlayer, err := sessionAttendance(h.db, session.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch attendance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session":    session,
+		"games":      gameSummaries,
+		"attendance": countAttendance(group.Players, byPlayer),
+	})
+}
+
+// CreateSession schedules a new session for a group
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	var req SessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session must end after it starts"})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	session := models.Session{
+		GroupID:  group.ID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Venue:    req.Venue,
+		Capacity: req.Capacity,
+	}
+	if req.CostCents != nil {
+		session.CostCents = *req.CostCents
+	}
+
+	if err := h.db.Create(&session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// UpdateSession updates an existing session
+func (h *SessionHandler) UpdateSession(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req SessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "session must end after it starts"})
+		return
+	}
+
+	session.StartsAt = req.StartsAt
+	session.EndsAt = req.EndsAt
+	session.Venue = req.Venue
+	session.Capacity = req.Capacity
+	if req.CostCents != nil {
+		session.CostCents = *req.CostCents
+	}
+
+	// Raising the capacity promotes players off the waitlist
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&session).Error; err != nil {
+			return err
+		}
+		return promoteWaitlist(tx, session)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// DeleteSession deletes a session. Games, season matches, charges and payments for it are
+// kept but unlinked.
+func (h *SessionHandler) DeleteSession(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	if err := deleteSession(h.db, session); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session deleted"})
+}
+
+// findSession loads the session from the :id and :session_id params, verifying the
+// authenticated user has at least minRole in the group. It writes the error response and
+// returns false if not.
+func (h *SessionHandler) findSession(c *gin.Context, minRole string) (models.Session, bool) {
+	sessionID, err := strconv.ParseUint(c.Param("session_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid session ID"})
+		return models.Session{}, false
+	}
+
+	group, ok := authorizeGroup(c, h.db, minRole)
+	if !ok {
+		return models.Session{}, false
+	}
+
+	var session models.Session
+	if err := h.db.Where("id = ? AND group_id = ?", sessionID, group.ID).First(&session).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return models.Session{}, false
+	}
+
+	return session, true
+}
+
+// deleteSession deletes a session with its attendance and event log, keeping any games,
+// season matches and ledger entries for it but unlinking them
+func deleteSession(db *gorm.DB, session models.Session) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Game{}).Where("session_id = ?", session.ID).Update("session_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SeasonMatch{}).Where("session_id = ?", session.ID).Update("session_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("session_id = ?", session.ID).Delete(&models.Attendance{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("session_id = ?", session.ID).Delete(&models.SessionEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.LedgerEntry{}).Where("session_id = ?", session.ID).Update("session_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&session).Error
+	})
+}
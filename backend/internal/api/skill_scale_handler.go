@@ -0,0 +1,251 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/auth"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxScaleLevels bounds how fine a skill scale can be
+const maxScaleLevels = 1000
+
+type SkillScaleHandler struct {
+	db *gorm.DB
+}
+
+func NewSkillScaleHandler(db *gorm.DB) *SkillScaleHandler {
+	return &SkillScaleHandler{db: db}
+}
+
+type SkillScaleRequest struct {
+	Name   string              `json:"name" binding:"required"`
+	Min    *float64            `json:"min" binding:"required"`
+	Max    *float64            `json:"max" binding:"required"`
+	Step   float64             `json:"step" binding:"required,gt=0"`
+	Labels []models.SkillLabel `json:"labels"`
+}
+
+type SetSkillScaleRequest struct {
+	SkillScaleID *uint `json:"skill_scale_id"` // nil resets to the default scale
+}
+
+// GetSkillScales returns all skill scales for the authenticated user
+func (h *SkillScaleHandler) GetSkillScales(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	var scales []models.SkillScale
+	if err := h.db.Where("user_id = ?", userID).Find(&scales).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch skill scales"})
+		return
+	}
+
+	c.JSON(http.StatusOK, scales)
+}
+
+// CreateSkillScale creates a new skill scale
+func (h *SkillScaleHandler) CreateSkillScale(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	var req SkillScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scale := models.SkillScale{
+		UserID: userID,
+		Name:   req.Name,
+		Min:    *req.Min,
+		Max:    *req.Max,
+		Step:   req.Step,
+		Labels: req.Labels,
+	}
+	if err := validateSkillScale(scale); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.db.Create(&scale).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create skill scale"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, scale)
+}
+
+// UpdateSkillScale updates an existing skill scale. Player weights are not changed, so any
+// that no longer fit are reported back for the owner to fix.
+func (h *SkillScaleHandler) UpdateSkillScale(c *gin.Context) {
+	userID := auth.GetUserID(c)
+	scaleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid skill scale ID"})
+		return
+	}
+
+	var scale models.SkillScale
+	if err := h.db.Where("id = ? AND user_id = ?", scaleID, userID).First(&scale).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "skill scale not found"})
+		return
+	}
+
+	var req SkillScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scale.Name = req.Name
+	scale.Min = *req.Min
+	scale.Max = *req.Max
+	scale.Step = req.Step
+	scale.Labels = req.Labels
+	if err := validateSkillScale(scale); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.db.Save(&scale).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update skill scale"})
+		return
+	}
+
+	c.JSON(http.StatusOK, scale)
+}
+
+// DeleteSkillScale deletes a skill scale that is not in use
+func (h *SkillScaleHandler) DeleteSkillScale(c *gin.Context) {
+	userID := auth.GetUserID(c)
+	scaleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid skill scale ID"})
+		return
+	}
+
+	var scale models.SkillScale
+	if err := h.db.Where("id = ? AND user_id = ?", scaleID, userID).First(&scale).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "skill scale not found"})
+		return
+	}
+
+	var inUse int64
+	if err := h.db.Model(&models.Group{}).Where("skill_scale_id = ?", scale.ID).Count(&inUse).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete skill scale"})
+		return
+	}
+	if inUse == 0 {
+		if err := h.db.Model(&models.User{}).Where("skill_scale_id = ?", scale.ID).Count(&inUse).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete skill scale"})
+			return
+		}
+	}
+	if inUse > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "skill scale is in use"})
+		return
+	}
+
+	if err := h.db.Delete(&scale).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete skill scale"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "skill scale deleted"})
+}
+
+// SetDefaultSkillScale sets the skill scale used for the user's players and groups
+func (h *SkillScaleHandler) SetDefaultSkillScale(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	var req SetSkillScaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scale := models.DefaultSkillScale
+	if req.SkillScaleID != nil {
+		if err := h.db.Where("id = ? AND user_id = ?", *req.SkillScaleID, userID).First(&scale).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "skill scale not found"})
+			return
+		}
+	}
+
+	if err := h.db.Model(&models.User{}).Where("id = ?", userID).Update("skill_scale_id", req.SkillScaleID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to set skill scale"})
+		return
+	}
+
+	// Report players whose weights don't fit the new scale
+	var players []models.Player
+	if err := h.db.Where("user_id = ?", userID).Find(&players).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch players"})
+		return
+	}
+
+	outOfRange := []uint{}
+	for _, p := range players {
+		if !scale.Contains(p.SkillWeight) {
+			outOfRange = append(outOfRange, p.ID)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"skill_scale":             scale,
+		"out_of_range_player_ids": outOfRange,
+	})
+}
+
+// validateSkillScale checks that a scale has a sensible range, step and labels
+func validateSkillScale(scale models.SkillScale) error {
+	if scale.Max <= scale.Min {
+		return errors.New("max must be greater than min")
+	}
+	if !scale.Contains(scale.Max) {
+		return errors.New("max must be reachable from min in whole steps")
+	}
+	if (scale.Max-scale.Min)/scale.Step > maxScaleLevels {
+		return errors.New("skill scale has too many levels")
+	}
+	for _, label := range scale.Labels {
+		if !scale.Contains(label.Value) {
+			return errors.New("label value is not on the scale")
+		}
+	}
+	return nil
+}
+
+// accountSkillScale returns the skill scale a user's players are weighted on
+func accountSkillScale(db *gorm.DB, userID uint) (models.SkillScale, error) {
+	var user models.User
+	if err := db.Select("id", "skill_scale_id").First(&user, userID).Error; err != nil {
+		return models.SkillScale{}, err
+	}
+	if user.SkillScaleID == nil {
+		return models.DefaultSkillScale, nil
+	}
+
+	var scale models.SkillScale
+	if err := db.First(&scale, *user.SkillScaleID).Error; err != nil {
+		return models.SkillScale{}, err
+	}
+	return scale, nil
+}
+
+// groupSkillScale returns the skill scale a group's weights are on: the group's own if
+// set, otherwise the owner's
+func groupSkillScale(db *gorm.DB, group models.Group) (models.SkillScale, error) {
+	if group.SkillScaleID == nil {
+		return accountSkillScale(db, group.UserID)
+	}
+
+	var scale models.SkillScale
+	if err := db.First(&scale, *group.SkillScaleID).Error; err != nil {
+		return models.SkillScale{}, err
+	}
+	return scale, nil
+}
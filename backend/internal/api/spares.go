@@ -0,0 +1,287 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// How much each factor counts towards a spare's replacement score
+const (
+	replacementSkillWeight      = 0.5
+	replacementPositionWeight   = 0.3
+	replacementAttendanceWeight = 0.2
+)
+
+var errPlayerNotInGroup = errors.New("player not in group")
+
+type SetSpareRequest struct {
+	IsSpare bool `json:"is_spare"`
+}
+
+type InviteReplacementRequest struct {
+	PlayerID uint  `json:"player_id" binding:"required"` // The player who dropped out
+	SpareID  *uint `json:"spare_id"`                     // Optional, defaults to the top ranked spare
+}
+
+// ReplacementCandidate is a spare ranked by how well they would replace a dropped player.
+// Each factor is from 0 to 1, higher is a better match.
+type ReplacementCandidate struct {
+	PlayerID    uint    `json:"player_id"`
+	Name        string  `json:"name"`
+	Position    string  `json:"position,omitempty"`
+	Status      string  `json:"status"`       // The spare's RSVP for the session, "" if they haven't responded
+	Skill       float64 `json:"skill"`        // How close their skill is to the dropped player's
+	PositionFit float64 `json:"position_fit"` // 1 for the same position, 0.5 if either is unknown
+	Attendance  float64 `json:"attendance"`   // 1 minus their no-show rate, 0.5 if they have no history
+	Score       float64 `json:"score"`
+}
+
+// SetSpare marks a player as one of the group's spares, or clears it. Spares are left out of
+// the "all" roster when generating teams and can't also be regulars.
+func (h *GroupHandler) SetSpare(c *gin.Context) {
+	playerID, err := strconv.ParseUint(c.Param("player_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player ID"})
+		return
+	}
+
+	var req SetSpareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var membership models.GroupPlayer
+	if err := h.db.Where("group_id = ? AND player_id = ?", group.ID, playerID).First(&membership).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not in group"})
+		return
+	}
+
+	updates := map[string]interface{}{"is_spare": req.IsSpare}
+	if req.IsSpare {
+		updates["is_regular"] = false
+	}
+	if err := h.db.Model(&membership).Where("group_id = ? AND player_id = ?", group.ID, playerID).
+		Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update player"})
+		return
+	}
+	membership.IsSpare = req.IsSpare
+	if req.IsSpare {
+		membership.IsRegular = false
+	}
+
+	c.JSON(http.StatusOK, membership)
+}
+
+// GetReplacements ranks the group's available spares as replacements for a player in a
+// session, by skill closeness, position and attendance record
+func (h *SessionHandler) GetReplacements(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	playerID, err := strconv.ParseUint(c.Query("player_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "player_id is required"})
+		return
+	}
+
+	candidates, err := rankReplacements(h.db, session, uint(playerID))
+	if errors.Is(err, errPlayerNotInGroup) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not found in group"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to rank replacements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, candidates)
+}
+
+// InviteReplacement brings a spare into a session in place of a player who dropped out,
+// choosing the top ranked spare unless one is given. The spare is put on the waitlist if the
+// session has filled up since.
+func (h *SessionHandler) InviteReplacement(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req InviteReplacementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dropped, err := loadAttendance(h.db, session.ID, req.PlayerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to invite replacement"})
+		return
+	}
+	// Only a player who RSVP'd out can be replaced, not one who never answered or is a maybe
+	if dropped.Status != models.RSVPOut {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "player hasn't dropped out"})
+		return
+	}
+
+	candidates, err := rankReplacements(h.db, session, req.PlayerID)
+	if errors.Is(err, errPlayerNotInGroup) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "player not found in group"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to invite replacement"})
+		return
+	}
+
+	var pick *ReplacementCandidate
+	for i := range candidates {
+		if req.SpareID == nil || candidates[i].PlayerID == *req.SpareID {
+			pick = &candidates[i]
+			break
+		}
+	}
+	if pick == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no available spare"})
+		return
+	}
+
+	var attendance models.Attendance
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if attendance, err = applyRSVP(tx, session, pick.PlayerID, models.RSVPIn, time.Now()); err != nil {
+			return err
+		}
+		return logSessionEvent(tx, session.ID, pick.PlayerID, models.SessionEventSubbedIn, fmt.Sprintf("replacing player %d", req.PlayerID))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to invite replacement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"replacement": pick,
+		"attendance":  attendance,
+	})
+}
+
+// rankReplacements scores the group's spares who aren't already in, waitlisted or out for the
+// session as replacements for playerID, best first
+func rankReplacements(tx *gorm.DB, session models.Session, playerID uint) ([]ReplacementCandidate, error) {
+	var group models.Group
+	if err := tx.Preload("Players").First(&group, session.GroupID).Error; err != nil {
+		return nil, err
+	}
+	if err := applySkillOverrides(tx, group.ID, group.Players); err != nil {
+		return nil, err
+	}
+	scale, err := groupSkillScale(tx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	spares, err := spareIDs(tx, group.ID)
+	if err != nil {
+		return nil, err
+	}
+	byPlayer, err := sessionAttendance(tx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := reliabilityStats(tx, group, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Compare on the weights teams are balanced with, on the default 1-5 scale
+	weights := balancingWeights(group, group.Players, scale)
+	skill := func(p models.Player) float64 {
+		if w, ok := weights[p.ID]; ok {
+			return scale.Normalize(w)
+		}
+		return scale.Normalize(p.SkillWeight)
+	}
+
+	var target *models.Player
+	for i := range group.Players {
+		if group.Players[i].ID == playerID {
+			target = &group.Players[i]
+		}
+	}
+	if target == nil {
+		return nil, errPlayerNotInGroup
+	}
+
+	candidates := []ReplacementCandidate{}
+	for _, p := range group.Players {
+		if !spares[p.ID] || p.ID == playerID {
+			continue
+		}
+		status := byPlayer[p.ID].Status
+		if status == models.RSVPIn || status == models.RSVPWaitlisted || status == models.RSVPOut {
+			continue
+		}
+
+		candidate := ReplacementCandidate{
+			PlayerID:    p.ID,
+			Name:        p.Name,
+			Position:    p.Position,
+			Status:      status,
+			Skill:       math.Max(0, 1-math.Abs(skill(p)-skill(*target))/4),
+			PositionFit: 0.5,
+			Attendance:  0.5,
+		}
+		if p.Position != "" && target.Position != "" {
+			candidate.PositionFit = 0
+			if p.Position == target.Position {
+				candidate.PositionFit = 1
+			}
+		}
+		if s, ok := stats[p.ID]; ok && s.NoShowRate != nil {
+			candidate.Attendance = 1 - *s.NoShowRate
+		}
+		candidate.Score = replacementSkillWeight*candidate.Skill +
+			replacementPositionWeight*candidate.PositionFit +
+			replacementAttendanceWeight*candidate.Attendance
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].PlayerID < candidates[j].PlayerID
+	})
+	return candidates, nil
+}
+
+// spareIDs returns the set of a group's spares
+func spareIDs(db *gorm.DB, groupID uint) (map[uint]bool, error) {
+	var ids []uint
+	if err := db.Model(&models.GroupPlayer{}).Where("group_id = ? AND is_spare = ?", groupID, true).
+		Pluck("player_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	spares := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		spares[id] = true
+	}
+	return spares, nil
+}
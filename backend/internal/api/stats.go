@@ -0,0 +1,189 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/stats"
+	"github.com/sticktoss/backend/internal/teamgen"
+	"gorm.io/gorm"
+)
+
+// TeammateCount is how many games a player has played on the same team as another
+type TeammateCount struct {
+	PlayerID uint   `json:"player_id"`
+	Name     string `json:"name"`
+	Games    int    `json:"games"`
+}
+
+// PlayerGameStats is a player's record across a group's recorded games
+type PlayerGameStats struct {
+	Name string `json:"name"`
+	stats.PlayerStats
+	Teammates []TeammateCount `json:"teammates"` // Most frequent first
+}
+
+// GetGroupStats aggregates per-player stats from the group's games with recorded results,
+// optionally limited to games generated in a date range or to one player
+func (h *GroupHandler) GetGroupStats(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var onlyPlayer uint
+	if value := c.Query("player_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid player_id"})
+			return
+		}
+		onlyPlayer = uint(id)
+	}
+
+	games, lineups, ok := loadGroupGames(c, h.db, group.ID, true)
+	if !ok {
+		return
+	}
+	names, positions, err := lineupPlayers(h.db, lineups)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch players"})
+		return
+	}
+
+	results := make([][]stats.Team, 0, len(games))
+	for i, game := range games {
+		results = append(results, statsTeams(game, lineups[i], positions))
+	}
+
+	players := []PlayerGameStats{}
+	for id, s := range stats.Aggregate(results) {
+		if onlyPlayer != 0 && id != onlyPlayer {
+			continue
+		}
+		s.GoalsAgainst = math.Round(s.GoalsAgainst*100) / 100
+		s.GoalDifferential = math.Round(s.GoalDifferential*100) / 100
+
+		teammates := make([]TeammateCount, 0, len(s.Teammates))
+		for mate, n := range s.Teammates {
+			teammates = append(teammates, TeammateCount{PlayerID: mate, Name: names[mate], Games: n})
+		}
+		sort.Slice(teammates, func(i, j int) bool {
+			if teammates[i].Games != teammates[j].Games {
+				return teammates[i].Games > teammates[j].Games
+			}
+			return teammates[i].PlayerID < teammates[j].PlayerID
+		})
+
+		players = append(players, PlayerGameStats{Name: names[id], PlayerStats: *s, Teammates: teammates})
+	}
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].GamesPlayed != players[j].GamesPlayed {
+			return players[i].GamesPlayed > players[j].GamesPlayed
+		}
+		return players[i].PlayerID < players[j].PlayerID
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"games":   len(games),
+		"players": players,
+	})
+}
+
+// loadGroupGames loads a group's games oldest first with their lineups, limited to games
+// generated between the from and to query params if given, and to games with recorded
+// results if recordedOnly. It writes the error response and returns false on failure.
+func loadGroupGames(c *gin.Context, db *gorm.DB, groupID uint, recordedOnly bool) ([]models.Game, [][]teamgen.Team, bool) {
+	query := preloadGameTeams(db).Preload("Scores").Where("group_id = ?", groupID)
+	if recordedOnly {
+		query = query.Where("result_recorded_at IS NOT NULL")
+	}
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date"})
+			return nil, nil, false
+		}
+		query = query.Where("created_at >= ?", t)
+	}
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date"})
+			return nil, nil, false
+		}
+		query = query.Where("created_at < ?", t)
+	}
+
+	var games []models.Game
+	if err := query.Order("created_at ASC").Find(&games).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch games"})
+		return nil, nil, false
+	}
+
+	lineups := make([][]teamgen.Team, len(games))
+	for i, game := range games {
+		lineups[i] = gameLineup(game)
+	}
+	return games, lineups, true
+}
+
+// lineupPlayers returns the current names and positions of everyone in the lineups. Lineups
+// are snapshots from when each game was generated, so names fall back to them for players
+// who have since been deleted.
+func lineupPlayers(db *gorm.DB, lineups [][]teamgen.Team) (map[uint]string, map[uint]string, error) {
+	names := make(map[uint]string)
+	for _, teams := range lineups {
+		for _, team := range teams {
+			for _, p := range team.Players {
+				names[p.ID] = p.Name
+			}
+		}
+	}
+
+	positions := make(map[uint]string, len(names))
+	if len(names) == 0 {
+		return names, positions, nil
+	}
+	ids := make([]uint, 0, len(names))
+	for id := range names {
+		ids = append(ids, id)
+	}
+	var current []models.Player
+	if err := db.Where("id IN ?", ids).Find(&current).Error; err != nil {
+		return nil, nil, err
+	}
+	for _, p := range current {
+		names[p.ID] = p.Name
+		positions[p.ID] = p.Position
+	}
+	return names, positions, nil
+}
+
+// statsTeams converts a game's lineup and scores for aggregation. A player is a goalie by
+// the position they had in the game, or their current one if it wasn't recorded then.
+func statsTeams(game models.Game, lineup []teamgen.Team, positions map[uint]string) []stats.Team {
+	scoreByTeam := make(map[int]int, len(game.Scores))
+	for _, s := range game.Scores {
+		scoreByTeam[s.TeamNumber] = s.Score
+	}
+
+	teams := make([]stats.Team, 0, len(lineup))
+	for _, team := range lineup {
+		t := stats.Team{Score: scoreByTeam[team.Number]}
+		for _, p := range team.Players {
+			position := p.Position
+			if position == "" {
+				position = positions[p.ID]
+			}
+			t.Players = append(t.Players, stats.Player{ID: p.ID, Goalie: position == models.PositionGoalie})
+		}
+		teams = append(teams, t)
+	}
+	return teams
+}
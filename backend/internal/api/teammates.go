@@ -0,0 +1,169 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/stats"
+)
+
+// Matrices the teammate CSV export can contain
+const (
+	MatrixTogether = "together"
+	MatrixApart    = "apart"
+	MatrixWinRate  = "win_rate"
+)
+
+// MatrixPlayer is one row and column of the teammate matrix
+type MatrixPlayer struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// TeammateMatrix compares every pair of a group's players. Rows and columns follow Players.
+type TeammateMatrix struct {
+	Games           int            `json:"games"`
+	RecordedGames   int            `json:"recorded_games"`
+	Players         []MatrixPlayer `json:"players"`
+	Together        [][]int        `json:"together"`          // Games on the same team, games played on the diagonal
+	Apart           [][]int        `json:"apart"`             // Games on opposing teams
+	WinRateTogether [][]*float64   `json:"win_rate_together"` // Share of recorded games together won, ties count half
+}
+
+// GetTeammateMatrix compares how often each pair of the group's players has been on the same
+// team and on opposing teams, and how often they win together, optionally limited to games
+// generated in a date range. With format=csv one matrix is exported, chosen by metric.
+func (h *GroupHandler) GetTeammateMatrix(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	metric := c.DefaultQuery("metric", MatrixTogether)
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or csv"})
+		return
+	}
+	if metric != MatrixTogether && metric != MatrixApart && metric != MatrixWinRate {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "metric must be together, apart or win_rate"})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db.Preload("Players"), models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	games, lineups, ok := loadGroupGames(c, h.db, group.ID, false)
+	if !ok {
+		return
+	}
+
+	teams := make([][]stats.Team, len(games))
+	recorded := make([]bool, len(games))
+	played := make(map[uint]int)
+	matrix := TeammateMatrix{Games: len(games), Players: []MatrixPlayer{}}
+	for i, game := range games {
+		teams[i] = statsTeams(game, lineups[i], nil)
+		recorded[i] = game.ResultRecordedAt != nil
+		if recorded[i] {
+			matrix.RecordedGames++
+		}
+		for _, team := range teams[i] {
+			for _, p := range team.Players {
+				played[p.ID]++
+			}
+		}
+	}
+	pairs := stats.Pairs(teams, recorded)
+
+	// Only the group's current players, alphabetically
+	for _, p := range group.Players {
+		matrix.Players = append(matrix.Players, MatrixPlayer{ID: p.ID, Name: p.Name})
+	}
+	sort.Slice(matrix.Players, func(i, j int) bool {
+		if matrix.Players[i].Name != matrix.Players[j].Name {
+			return matrix.Players[i].Name < matrix.Players[j].Name
+		}
+		return matrix.Players[i].ID < matrix.Players[j].ID
+	})
+
+	n := len(matrix.Players)
+	matrix.Together = make([][]int, n)
+	matrix.Apart = make([][]int, n)
+	matrix.WinRateTogether = make([][]*float64, n)
+	for i, a := range matrix.Players {
+		matrix.Together[i] = make([]int, n)
+		matrix.Apart[i] = make([]int, n)
+		matrix.WinRateTogether[i] = make([]*float64, n)
+		for j, b := range matrix.Players {
+			if i == j {
+				matrix.Together[i][j] = played[a.ID]
+				continue
+			}
+			pair, ok := pairs[stats.NewPair(a.ID, b.ID)]
+			if !ok {
+				continue
+			}
+			matrix.Together[i][j] = pair.Together
+			matrix.Apart[i][j] = pair.Apart
+			if pair.Recorded > 0 {
+				rate := math.Round(pair.WinsTogether/float64(pair.Recorded)*1000) / 1000
+				matrix.WinRateTogether[i][j] = &rate
+			}
+		}
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, matrix)
+		return
+	}
+
+	// Write to a buffer first so a failure can still be reported as an error
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"player"}
+	for _, p := range matrix.Players {
+		header = append(header, csvText(p.Name))
+	}
+	w.Write(header)
+	for i, p := range matrix.Players {
+		row := []string{csvText(p.Name)}
+		for j := range matrix.Players {
+			switch metric {
+			case MatrixTogether:
+				row = append(row, strconv.Itoa(matrix.Together[i][j]))
+			case MatrixApart:
+				row = append(row, strconv.Itoa(matrix.Apart[i][j]))
+			case MatrixWinRate:
+				value := ""
+				if rate := matrix.WinRateTogether[i][j]; rate != nil {
+					value = strconv.FormatFloat(*rate, 'f', 3, 64)
+				}
+				row = append(row, value)
+			}
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export teammates"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"teammates-%s.csv\"", metric))
+	c.Data(http.StatusOK, "text/csv", buf.Bytes())
+}
+
+// csvText escapes text that spreadsheets would otherwise run as a formula when the CSV is
+// opened, by starting it with an apostrophe
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,532 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/league"
+	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/tournament"
+	"gorm.io/gorm"
+)
+
+// Pool standings points, fixed since pool play only decides who goes through
+var poolPoints = league.Points{Win: 2, Tie: 1, Loss: 0}
+
+type TournamentHandler struct {
+	db *gorm.DB
+}
+
+func NewTournamentHandler(db *gorm.DB) *TournamentHandler {
+	return &TournamentHandler{db: db}
+}
+
+type CreateTournamentRequest struct {
+	Name           string `json:"name" binding:"required"`
+	GameShareID    string `json:"game_share_id" binding:"required"`
+	Pools          int    `json:"pools" binding:"min=0"`                                // Optional, 0 goes straight to the bracket
+	AdvancePerPool int    `json:"advance_per_pool" binding:"min=0"`                     // Optional, defaults to 2 when pools feed a bracket
+	Bracket        string `json:"bracket" binding:"omitempty,oneof=none single double"` // Optional, defaults to "single"
+}
+
+// PoolTeamStanding is a team's row in its pool's standings. Team is the team's number in the game.
+type PoolTeamStanding struct {
+	TeamID uint   `json:"team_id"`
+	Name   string `json:"name"`
+	tournament.PoolStanding
+}
+
+// TournamentDetail is a tournament with its teams, matches, pool standings and champion
+type TournamentDetail struct {
+	models.Tournament
+	Standings [][]PoolTeamStanding   `json:"standings,omitempty"`
+	Champion  *models.TournamentTeam `json:"champion"`
+}
+
+// GetTournaments returns a group's tournaments
+func (h *TournamentHandler) GetTournaments(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var tournaments []models.Tournament
+	if err := h.db.Where("group_id = ?", group.ID).Order("created_at DESC").Find(&tournaments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch tournaments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tournaments)
+}
+
+// GetTournament returns a tournament with its teams, matches, pool standings and champion
+func (h *TournamentHandler) GetTournament(c *gin.Context) {
+	t, ok := h.findTournament(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	detail, err := tournamentDetail(h.db, t)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch tournament"})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// CreateTournament starts a tournament between the teams of one of the group's games, seeded
+// by team strength. Teams play round-robin pools, an elimination bracket, or pools whose top
+// teams go on to the bracket once pool play is over.
+func (h *TournamentHandler) CreateTournament(c *gin.Context) {
+	var req CreateTournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Bracket == "" {
+		req.Bracket = models.TournamentBracketSingle
+	}
+
+	group, ok := authorizeGroup(c, h.db, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var game models.Game
+	if err := preloadGameTeams(h.db).Where("share_id = ? AND group_id = ?", req.GameShareID, group.ID).First(&game).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "game not found"})
+		return
+	}
+
+	teams := gameLineup(game)
+	if len(teams) < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a tournament needs at least two teams"})
+		return
+	}
+
+	// Seed the strongest team first
+	sort.SliceStable(teams, func(i, j int) bool {
+		if teams[i].TotalWeight != teams[j].TotalWeight {
+			return teams[i].TotalWeight > teams[j].TotalWeight
+		}
+		return teams[i].Number < teams[j].Number
+	})
+
+	if req.Pools == 0 {
+		if req.Bracket == models.TournamentBracketNone {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "a tournament without a bracket needs at least one pool"})
+			return
+		}
+		req.AdvancePerPool = 0
+	} else {
+		if req.Pools > len(teams)/2 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "each pool needs at least two teams"})
+			return
+		}
+		if req.Bracket == models.TournamentBracketNone {
+			req.AdvancePerPool = 0
+		} else {
+			if req.AdvancePerPool == 0 {
+				req.AdvancePerPool = 2
+			}
+			if req.AdvancePerPool > len(teams)/req.Pools {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "advance_per_pool is more than the smallest pool"})
+				return
+			}
+			if req.AdvancePerPool*req.Pools < 2 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "at least two teams must advance to the bracket"})
+				return
+			}
+		}
+	}
+
+	t := models.Tournament{
+		GroupID:        group.ID,
+		GameShareID:    game.ShareID,
+		Name:           req.Name,
+		Pools:          req.Pools,
+		AdvancePerPool: req.AdvancePerPool,
+		Bracket:        req.Bracket,
+	}
+
+	seeds := make([]int, len(teams))
+	for i, team := range teams {
+		seeds[i] = i + 1
+		t.Teams = append(t.Teams, models.TournamentTeam{
+			Seed:       i + 1,
+			TeamNumber: team.Number,
+			Name:       fmt.Sprintf("Team %d", team.Number),
+			Strength:   team.TotalWeight,
+		})
+	}
+
+	var matches []tournament.Match
+	if req.Pools > 0 {
+		pools := tournament.Pools(len(teams), req.Pools)
+		for p, poolSeeds := range pools {
+			for _, seed := range poolSeeds {
+				t.Teams[seed-1].Pool = p + 1
+			}
+		}
+		matches = tournament.PoolMatches(pools)
+	} else {
+		matches = bracketMatches(req.Bracket, seeds)
+	}
+	t.Matches = matchRows(matches, 0)
+
+	if err := h.db.Create(&t).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create tournament"})
+		return
+	}
+
+	detail, err := tournamentDetail(h.db, t)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch tournament"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
+}
+
+// DeleteTournament deletes a tournament with its teams and matches
+func (h *TournamentHandler) DeleteTournament(c *gin.Context) {
+	t, ok := h.findTournament(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		return deleteTournaments(tx, []uint{t.ID})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete tournament"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "tournament deleted"})
+}
+
+// RecordTournamentResult records or corrects the score of a tournament match. Bracket winners
+// move on to their next match, and the bracket is drawn from the pool standings once the last
+// pool match is recorded.
+func (h *TournamentHandler) RecordTournamentResult(c *gin.Context) {
+	var req MatchResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	t, ok := h.findTournament(c, models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	matchID, err := strconv.ParseUint(c.Param("match_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid match ID"})
+		return
+	}
+
+	var rows []models.TournamentMatch
+	if err := h.db.Where("tournament_id = ?", t.ID).Order("number ASC").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record result"})
+		return
+	}
+	index := -1
+	for i, row := range rows {
+		if uint64(row.ID) == matchID {
+			index = i
+		}
+	}
+	if index < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "match not found"})
+		return
+	}
+
+	matches := toMatches(rows)
+	m := &matches[index]
+	home, away := *req.HomeScore, *req.AwayScore
+
+	if m.Bracket == tournament.BracketPool {
+		for _, row := range rows {
+			if row.Bracket != tournament.BracketPool {
+				c.JSON(http.StatusConflict, gin.H{"error": "pool results are final once the bracket is drawn"})
+				return
+			}
+		}
+		m.Done = true
+		m.Winner = 0
+		if home > away {
+			m.Winner = m.Teams[0]
+		} else if away > home {
+			m.Winner = m.Teams[1]
+		}
+	} else {
+		if home == away {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bracket matches can't end in a tie"})
+			return
+		}
+		winnerSide := 0
+		if away > home {
+			winnerSide = 1
+		}
+		err := tournament.Record(matches, index, winnerSide)
+		if errors.Is(err, tournament.ErrNotReady) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, tournament.ErrLocked) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	now := time.Now()
+	rows[index].HomeScore = req.HomeScore
+	rows[index].AwayScore = req.AwayScore
+	rows[index].ResultRecordedAt = &now
+	for i := range rows {
+		if i != index && !matches[i].Done {
+			// Matches undone by a correction lose their scores too
+			rows[i].HomeScore = nil
+			rows[i].AwayScore = nil
+			rows[i].ResultRecordedAt = nil
+		}
+	}
+	applyMatches(rows, matches)
+
+	var drawn []models.TournamentMatch
+	if m.Bracket == tournament.BracketPool && t.Bracket != models.TournamentBracketNone && poolPlayOver(matches) {
+		pools, err := tournamentPools(h.db, t)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record result"})
+			return
+		}
+		standings := tournament.PoolStandings(pools, matches, matchScores(rows), poolPoints)
+		qualifiers := tournament.Qualifiers(standings, t.AdvancePerPool)
+		drawn = matchRows(bracketMatches(t.Bracket, qualifiers), len(rows))
+		for i := range drawn {
+			drawn[i].TournamentID = t.ID
+		}
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		for i := range rows {
+			if err := tx.Save(&rows[i]).Error; err != nil {
+				return err
+			}
+		}
+		if len(drawn) > 0 {
+			return tx.Create(&drawn).Error
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record result"})
+		return
+	}
+
+	detail, err := tournamentDetail(h.db, t)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch tournament"})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// findTournament loads the tournament from the :id and :tournament_id params, verifying the
+// authenticated user has at least minRole in the group. It writes the error response and
+// returns false if not.
+func (h *TournamentHandler) findTournament(c *gin.Context, minRole string) (models.Tournament, bool) {
+	tournamentID, err := strconv.ParseUint(c.Param("tournament_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tournament ID"})
+		return models.Tournament{}, false
+	}
+
+	group, ok := authorizeGroup(c, h.db, minRole)
+	if !ok {
+		return models.Tournament{}, false
+	}
+
+	var t models.Tournament
+	if err := h.db.Where("id = ? AND group_id = ?", tournamentID, group.ID).First(&t).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "tournament not found"})
+		return models.Tournament{}, false
+	}
+
+	return t, true
+}
+
+// tournamentDetail loads a tournament's teams and matches and works out its pool standings
+// and champion
+func tournamentDetail(db *gorm.DB, t models.Tournament) (TournamentDetail, error) {
+	if err := db.Preload("Teams", func(db *gorm.DB) *gorm.DB {
+		return db.Order("seed ASC")
+	}).Preload("Matches", func(db *gorm.DB) *gorm.DB {
+		return db.Order("number ASC")
+	}).First(&t, t.ID).Error; err != nil {
+		return TournamentDetail{}, err
+	}
+
+	detail := TournamentDetail{Tournament: t}
+	bySeed := make(map[int]models.TournamentTeam, len(t.Teams))
+	for _, team := range t.Teams {
+		bySeed[team.Seed] = team
+	}
+	matches := toMatches(t.Matches)
+
+	if t.Pools > 0 {
+		pools := make([][]int, t.Pools)
+		for _, team := range t.Teams {
+			pools[team.Pool-1] = append(pools[team.Pool-1], team.Seed)
+		}
+		for _, pool := range tournament.PoolStandings(pools, matches, matchScores(t.Matches), poolPoints) {
+			rows := make([]PoolTeamStanding, 0, len(pool))
+			for _, s := range pool {
+				team := bySeed[s.Seed]
+				s.Team = team.TeamNumber
+				rows = append(rows, PoolTeamStanding{TeamID: team.ID, Name: team.Name, PoolStanding: s})
+			}
+			detail.Standings = append(detail.Standings, rows)
+		}
+	}
+
+	champion := tournament.Champion(matches)
+	if t.Bracket == models.TournamentBracketNone && t.Pools == 1 && poolPlayOver(matches) {
+		champion = detail.Standings[0][0].Seed
+	}
+	if team, ok := bySeed[champion]; ok {
+		detail.Champion = &team
+	}
+
+	return detail, nil
+}
+
+// tournamentPools returns the seeds in each of a tournament's pools
+func tournamentPools(db *gorm.DB, t models.Tournament) ([][]int, error) {
+	var teams []models.TournamentTeam
+	if err := db.Where("tournament_id = ?", t.ID).Order("seed ASC").Find(&teams).Error; err != nil {
+		return nil, err
+	}
+	pools := make([][]int, t.Pools)
+	for _, team := range teams {
+		pools[team.Pool-1] = append(pools[team.Pool-1], team.Seed)
+	}
+	return pools, nil
+}
+
+// bracketMatches draws an elimination bracket for seeds, strongest first
+func bracketMatches(bracket string, seeds []int) []tournament.Match {
+	if bracket == models.TournamentBracketDouble {
+		return tournament.DoubleElimination(seeds)
+	}
+	return tournament.SingleElimination(seeds)
+}
+
+// poolPlayOver reports whether there are pool matches and all of them have been played
+func poolPlayOver(matches []tournament.Match) bool {
+	found := false
+	for _, m := range matches {
+		if m.Bracket != tournament.BracketPool {
+			continue
+		}
+		if !m.Done {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// matchRows converts matches into rows numbered after offset existing ones
+func matchRows(matches []tournament.Match, offset int) []models.TournamentMatch {
+	rows := make([]models.TournamentMatch, len(matches))
+	for i, m := range matches {
+		rows[i] = models.TournamentMatch{
+			Number:  offset + i + 1,
+			Bracket: m.Bracket,
+			Pool:    m.Pool,
+			Round:   m.Round,
+		}
+		if m.WinnerTo != nil {
+			rows[i].WinnerToNumber = offset + m.WinnerTo.Match + 1
+			rows[i].WinnerToSide = m.WinnerTo.Side
+		}
+		if m.LoserTo != nil {
+			rows[i].LoserToNumber = offset + m.LoserTo.Match + 1
+			rows[i].LoserToSide = m.LoserTo.Side
+		}
+	}
+	applyMatches(rows, matches)
+	return rows
+}
+
+// applyMatches copies the teams and results of matches onto their rows
+func applyMatches(rows []models.TournamentMatch, matches []tournament.Match) {
+	for i, m := range matches {
+		rows[i].HomeSeed = m.Teams[0]
+		rows[i].AwaySeed = m.Teams[1]
+		rows[i].HomeBye = m.Byes[0]
+		rows[i].AwayBye = m.Byes[1]
+		rows[i].WinnerSeed = m.Winner
+		rows[i].Completed = m.Done
+		rows[i].Skipped = m.Skipped
+	}
+}
+
+// toMatches converts rows ordered by number back into matches
+func toMatches(rows []models.TournamentMatch) []tournament.Match {
+	matches := make([]tournament.Match, len(rows))
+	for i, row := range rows {
+		matches[i] = tournament.Match{
+			Bracket: row.Bracket,
+			Pool:    row.Pool,
+			Round:   row.Round,
+			Teams:   [2]int{row.HomeSeed, row.AwaySeed},
+			Byes:    [2]bool{row.HomeBye, row.AwayBye},
+			Winner:  row.WinnerSeed,
+			Done:    row.Completed,
+			Skipped: row.Skipped,
+		}
+		if row.WinnerToNumber > 0 {
+			matches[i].WinnerTo = &tournament.Slot{Match: row.WinnerToNumber - 1, Side: row.WinnerToSide}
+		}
+		if row.LoserToNumber > 0 {
+			matches[i].LoserTo = &tournament.Slot{Match: row.LoserToNumber - 1, Side: row.LoserToSide}
+		}
+	}
+	return matches
+}
+
+// matchScores returns each row's home and away score, 0 if not recorded
+func matchScores(rows []models.TournamentMatch) [][2]int {
+	scores := make([][2]int, len(rows))
+	for i, row := range rows {
+		if row.HomeScore != nil && row.AwayScore != nil {
+			scores[i] = [2]int{*row.HomeScore, *row.AwayScore}
+		}
+	}
+	return scores
+}
+
+// deleteTournaments deletes tournaments with their teams and matches
+func deleteTournaments(tx *gorm.DB, tournamentIDs []uint) error {
+	if len(tournamentIDs) == 0 {
+		return nil
+	}
+	if err := tx.Where("tournament_id IN ?", tournamentIDs).Delete(&models.TournamentTeam{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("tournament_id IN ?", tournamentIDs).Delete(&models.TournamentMatch{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", tournamentIDs).Delete(&models.Tournament{}).Error
+}
@@ -0,0 +1,304 @@
+package api
+
+import (
+	"math/rand"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetSessionEvents returns a session's waitlist and drop-out log, oldest first
+func (h *SessionHandler) GetSessionEvents(c *gin.Context) {
+	session, ok := h.findSession(c, models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	var events []models.SessionEvent
+	if err := h.db.Where("session_id = ?", session.ID).Order("created_at ASC, id ASC").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch session events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// applyRSVP saves a player's new RSVP and returns their attendance. A player who wants in to
+// a full session is put on the waitlist instead, and a confirmed player dropping out promotes
+// the next waitlisted one.
+func applyRSVP(tx *gorm.DB, session models.Session, playerID uint, status string, now time.Time) (models.Attendance, error) {
+	// Concurrent RSVPs could otherwise both take the last spot, or act on a stale status
+	if err := lockSession(tx, session.ID); err != nil {
+		return models.Attendance{}, err
+	}
+	attendance, err := loadAttendance(tx, session.ID, playerID)
+	if err != nil {
+		return models.Attendance{}, err
+	}
+	prev := attendance.Status
+
+	if status == models.RSVPIn && prev != models.RSVPIn {
+		if prev == models.RSVPWaitlisted {
+			// Keep their place in line
+			status = models.RSVPWaitlisted
+		} else {
+			full, err := sessionFull(tx, session)
+			if err != nil {
+				return models.Attendance{}, err
+			}
+			if full {
+				status = models.RSVPWaitlisted
+				attendance.WaitlistedAt = &now
+				if err := logSessionEvent(tx, session.ID, playerID, models.SessionEventWaitlisted, ""); err != nil {
+					return models.Attendance{}, err
+				}
+			}
+		}
+	}
+
+	if status != models.RSVPWaitlisted {
+		attendance.WaitlistedAt = nil
+	}
+	// A player who drops out is no longer at the rink
+	if status == models.RSVPOut {
+		attendance.CheckedInAt = nil
+	}
+	attendance.Status = status
+
+	if err := tx.Save(&attendance).Error; err != nil {
+		return models.Attendance{}, err
+	}
+
+	if prev == models.RSVPIn && status != models.RSVPIn {
+		if err := logSessionEvent(tx, session.ID, playerID, models.SessionEventDropped, status); err != nil {
+			return models.Attendance{}, err
+		}
+		if err := promoteWaitlist(tx, session); err != nil {
+			return models.Attendance{}, err
+		}
+	}
+	return attendance, nil
+}
+
+// promoteWaitlist moves waitlisted players into any open spots in a session, in the order
+// given by the group's waitlist priority
+func promoteWaitlist(tx *gorm.DB, session models.Session) error {
+	if err := lockSession(tx, session.ID); err != nil {
+		return err
+	}
+
+	open := -1 // unlimited
+	if session.Capacity > 0 {
+		confirmed, err := confirmedCount(tx, session.ID)
+		if err != nil {
+			return err
+		}
+		open = session.Capacity - int(confirmed)
+		if open <= 0 {
+			return nil
+		}
+	}
+
+	var waitlisted []models.Attendance
+	if err := tx.Where("session_id = ? AND status = ?", session.ID, models.RSVPWaitlisted).Find(&waitlisted).Error; err != nil {
+		return err
+	}
+	if len(waitlisted) == 0 {
+		return nil
+	}
+
+	var group models.Group
+	if err := tx.First(&group, session.GroupID).Error; err != nil {
+		return err
+	}
+
+	ordered, err := waitlistOrder(tx, session, group, waitlisted)
+	if err != nil {
+		return err
+	}
+
+	for i, a := range ordered {
+		if open >= 0 && i >= open {
+			break
+		}
+		a.Status = models.RSVPIn
+		a.WaitlistedAt = nil
+		if err := tx.Save(&a).Error; err != nil {
+			return err
+		}
+		if err := logSessionEvent(tx, session.ID, a.PlayerID, models.SessionEventPromoted, group.WaitlistPriority); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitlistOrder sorts waitlisted players by who should be promoted first. Ties, and the
+// first_come rule, go to whoever joined the waitlist earliest. Players caught by the group's
+// no-show penalty go after everyone else.
+func waitlistOrder(tx *gorm.DB, session models.Session, group models.Group, waitlisted []models.Attendance) ([]models.Attendance, error) {
+	ordered := append([]models.Attendance(nil), waitlisted...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return waitlistedBefore(ordered[i], ordered[j])
+	})
+
+	playerIDs := make([]uint, 0, len(ordered))
+	for _, a := range ordered {
+		playerIDs = append(playerIDs, a.PlayerID)
+	}
+
+	switch group.WaitlistPriority {
+	case models.WaitlistRegulars:
+		var regulars []uint
+		if err := tx.Model(&models.GroupPlayer{}).
+			Where("group_id = ? AND player_id IN ? AND is_regular = ?", session.GroupID, playerIDs, true).
+			Pluck("player_id", &regulars).Error; err != nil {
+			return nil, err
+		}
+		isRegular := make(map[uint]bool, len(regulars))
+		for _, id := range regulars {
+			isRegular[id] = true
+		}
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return isRegular[ordered[i].PlayerID] && !isRegular[ordered[j].PlayerID]
+		})
+
+	case models.WaitlistLottery:
+		rand.Shuffle(len(ordered), func(i, j int) {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		})
+
+	case models.WaitlistLeastRecent:
+		lastPlayed, err := lastPlayedBefore(tx, session, playerIDs)
+		if err != nil {
+			return nil, err
+		}
+		// Players who haven't played yet go first
+		sort.SliceStable(ordered, func(i, j int) bool {
+			ti, okI := lastPlayed[ordered[i].PlayerID]
+			tj, okJ := lastPlayed[ordered[j].PlayerID]
+			if !okI || !okJ {
+				return !okI && okJ
+			}
+			return ti.Before(tj)
+		})
+	}
+
+	if group.NoShowPenaltyRate > 0 {
+		stats, err := reliabilityStats(tx, group, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		isPenalized := func(playerID uint) bool {
+			s, ok := stats[playerID]
+			return ok && penalized(group, *s)
+		}
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return !isPenalized(ordered[i].PlayerID) && isPenalized(ordered[j].PlayerID)
+		})
+	}
+
+	return ordered, nil
+}
+
+// lastPlayedBefore returns when each player last played in one of the group's earlier sessions
+func lastPlayedBefore(tx *gorm.DB, session models.Session, playerIDs []uint) (map[uint]time.Time, error) {
+	var rows []struct {
+		PlayerID uint
+		StartsAt time.Time
+	}
+	if err := tx.Table("attendances").
+		Select("attendances.player_id, sessions.starts_at").
+		Joins("JOIN sessions ON sessions.id = attendances.session_id").
+		Where("sessions.group_id = ? AND sessions.starts_at < ? AND attendances.status = ? AND attendances.player_id IN ?",
+			session.GroupID, session.StartsAt, models.RSVPIn, playerIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	lastPlayed := make(map[uint]time.Time, len(rows))
+	for _, r := range rows {
+		if r.StartsAt.After(lastPlayed[r.PlayerID]) {
+			lastPlayed[r.PlayerID] = r.StartsAt
+		}
+	}
+	return lastPlayed, nil
+}
+
+// waitlistedBefore reports whether a joined the waitlist before b
+func waitlistedBefore(a, b models.Attendance) bool {
+	if a.WaitlistedAt == nil || b.WaitlistedAt == nil {
+		return a.WaitlistedAt != nil && b.WaitlistedAt == nil
+	}
+	if !a.WaitlistedAt.Equal(*b.WaitlistedAt) {
+		return a.WaitlistedAt.Before(*b.WaitlistedAt)
+	}
+	return a.PlayerID < b.PlayerID
+}
+
+// sessionFull reports whether a capacity-limited session has no open spots
+func sessionFull(tx *gorm.DB, session models.Session) (bool, error) {
+	if session.Capacity == 0 {
+		return false, nil
+	}
+	confirmed, err := confirmedCount(tx, session.ID)
+	if err != nil {
+		return false, err
+	}
+	return confirmed >= int64(session.Capacity), nil
+}
+
+// lockSession locks a session's row until the end of the transaction, so spots are counted
+// and taken one RSVP at a time. SQLite ignores the lock but only allows one writer anyway.
+func lockSession(tx *gorm.DB, sessionID uint) error {
+	var session models.Session
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&session, sessionID).Error
+}
+
+// withdrawPlayer deletes a player's RSVPs for the sessions of the given groups that haven't
+// started yet, promoting waitlisted players into any spots they held. Attendance at past
+// sessions is kept for reliability stats.
+func withdrawPlayer(tx *gorm.DB, playerID uint, groupIDs []uint, now time.Time) error {
+	if len(groupIDs) == 0 {
+		return nil
+	}
+
+	var sessions []models.Session
+	if err := tx.Where("group_id IN ? AND starts_at > ? AND id IN (?)", groupIDs, now,
+		tx.Model(&models.Attendance{}).Select("session_id").Where("player_id = ?", playerID)).
+		Find(&sessions).Error; err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := tx.Where("session_id = ? AND player_id = ?", session.ID, playerID).Delete(&models.Attendance{}).Error; err != nil {
+			return err
+		}
+		if err := promoteWaitlist(tx, session); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// confirmedCount returns how many players are in for a session
+func confirmedCount(tx *gorm.DB, sessionID uint) (int64, error) {
+	var count int64
+	err := tx.Model(&models.Attendance{}).Where("session_id = ? AND status = ?", sessionID, models.RSVPIn).Count(&count).Error
+	return count, err
+}
+
+// logSessionEvent adds an entry to a session's event log
+func logSessionEvent(tx *gorm.DB, sessionID, playerID uint, eventType, detail string) error {
+	return tx.Create(&models.SessionEvent{
+		SessionID: sessionID,
+		PlayerID:  playerID,
+		Type:      eventType,
+		Detail:    detail,
+	}).Error
+}
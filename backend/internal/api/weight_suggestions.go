@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sticktoss/backend/internal/auth"
+	"github.com/sticktoss/backend/internal/models"
+	"github.com/sticktoss/backend/internal/rating"
+	"gorm.io/gorm"
+)
+
+type ApplyWeightSuggestionsRequest struct {
+	PlayerIDs     []uint `json:"player_ids"`                                               // Optional, applies every suggestion when empty
+	MinConfidence string `json:"min_confidence" binding:"omitempty,oneof=low medium high"` // Optional, skip less confident suggestions
+}
+
+// GetWeightSuggestions recommends skill weight changes for a group's players based on how
+// their recorded games went compared to what the team weights predicted
+func (h *GroupHandler) GetWeightSuggestions(c *gin.Context) {
+	group, ok := authorizeGroup(c, h.db.Preload("Players"), models.GroupRoleViewer)
+	if !ok {
+		return
+	}
+
+	suggestions, err := h.weightSuggestions(group)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to analyze games"})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
+
+// ApplyWeightSuggestions accepts suggested skill weight changes in bulk, all or none
+func (h *GroupHandler) ApplyWeightSuggestions(c *gin.Context) {
+	userID := auth.GetUserID(c)
+
+	// The body is optional, so an empty one applies every suggestion
+	var req ApplyWeightSuggestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group, ok := authorizeGroup(c, h.db.Preload("Players"), models.GroupRoleAdmin)
+	if !ok {
+		return
+	}
+
+	// Suggestions are recomputed rather than trusted from the client
+	suggestions, err := h.weightSuggestions(group)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to analyze games"})
+		return
+	}
+
+	selected := make(map[uint]bool)
+	for _, id := range req.PlayerIDs {
+		selected[id] = true
+	}
+
+	// Suggestions are on the group's scale and based on its games, so they're saved as group
+	// overrides rather than changing the players' weights in every other group
+	applied := []rating.Suggestion{}
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		for _, s := range suggestions {
+			if len(selected) > 0 && !selected[s.PlayerID] {
+				continue
+			}
+			if req.MinConfidence != "" && rating.ConfidenceRank(s.Confidence) < rating.ConfidenceRank(req.MinConfidence) {
+				continue
+			}
+
+			if err := tx.Model(&models.GroupPlayer{}).Where("group_id = ? AND player_id = ?", group.ID, s.PlayerID).
+				Update("skill_weight", s.SuggestedWeight).Error; err != nil {
+				return err
+			}
+			if err := recordWeightChange(tx, s.PlayerID, &group.ID, &s.CurrentWeight, &s.SuggestedWeight, userID, models.WeightSourceSuggestion); err != nil {
+				return err
+			}
+			applied = append(applied, s)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update player"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"applied": applied})
+}
+
+// weightSuggestions analyzes every game of the group that has a recorded result.
+// Suggestions are relative to the weights the group uses, including overrides.
+func (h *GroupHandler) weightSuggestions(group models.Group) ([]rating.Suggestion, error) {
+	if err := applySkillOverrides(h.db, group.ID, group.Players); err != nil {
+		return nil, err
+	}
+
+	scale, err := groupSkillScale(h.db, group)
+	if err != nil {
+		return nil, err
+	}
+
+	var games []models.Game
+	if err := preloadGameTeams(h.db).Preload("Scores").
+		Where("group_id = ? AND result_recorded_at IS NOT NULL", group.ID).
+		Order("created_at ASC").
+		Find(&games).Error; err != nil {
+		return nil, err
+	}
+
+	outcomes := make([][]rating.TeamOutcome, 0, len(games))
+	for _, game := range games {
+		teams := gameLineup(game)
+
+		scoreByTeam := make(map[int]int)
+		for _, s := range game.Scores {
+			scoreByTeam[s.TeamNumber] = s.Score
+		}
+
+		gameOutcome := make([]rating.TeamOutcome, 0, len(teams))
+		for _, team := range teams {
+			// Put the team's weight on the 1-5 scale the outcome prediction expects
+			totalWeight := team.TotalWeight
+			if n := float64(len(team.Players)); n > 0 {
+				totalWeight = scale.Normalize(team.TotalWeight/n) * n
+			}
+			outcome := rating.TeamOutcome{TotalWeight: totalWeight, Score: scoreByTeam[team.Number]}
+			for _, p := range team.Players {
+				outcome.Players = append(outcome.Players, rating.PlayerWeight{PlayerID: p.ID, Weight: p.SkillWeight})
+			}
+			gameOutcome = append(gameOutcome, outcome)
+		}
+		outcomes = append(outcomes, gameOutcome)
+	}
+
+	currentWeights := make(map[uint]float64, len(group.Players))
+	for _, p := range group.Players {
+		currentWeights[p.ID] = p.SkillWeight
+	}
+
+	suggestions := rating.Suggest(outcomes, currentWeights, scale.Step, scale.Min, scale.Max)
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].PlayerID < suggestions[j].PlayerID
+	})
+	return suggestions, nil
+}
@@ -0,0 +1,179 @@
+package gamedata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// CurrentVersion is the schema version teams data is written in. Bump it and add an upgrade
+// from the previous version whenever the stored format changes.
+const CurrentVersion = 3
+
+// Player is a snapshot of a player as they were when their team was saved
+type Player struct {
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	SkillWeight float64 `json:"skill_weight"`
+	// BalanceWeight is the weight the player was balanced with, which differs from their
+	// skill weight when the group balances on ratings
+	BalanceWeight float64 `json:"balance_weight"`
+	Position      string  `json:"position,omitempty"`
+}
+
+// Team is one stored team of a game
+type Team struct {
+	Number      int      `json:"number"`
+	TotalWeight float64  `json:"total_weight"`
+	Players     []Player `json:"players"`
+}
+
+// document is how teams data is stored from version 2 on
+type document struct {
+	Version int             `json:"version"`
+	Teams   json.RawMessage `json:"teams"`
+}
+
+// upgrades converts the teams of each version into the next version's format
+var upgrades = map[int]func(json.RawMessage) (json.RawMessage, error){
+	1: upgradeV1,
+	2: upgradeV2,
+}
+
+// Encode stores teams in the current version
+func Encode(teams []Team) ([]byte, error) {
+	if teams == nil {
+		teams = []Team{}
+	}
+	data, err := json.Marshal(teams)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(document{Version: CurrentVersion, Teams: data})
+}
+
+// Decode reads teams data of any version, upgrading it to the current format. It also
+// returns the version the data was stored in.
+func Decode(data []byte) ([]Team, int, error) {
+	version, raw, err := parse(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if version > CurrentVersion {
+		return nil, 0, fmt.Errorf("unsupported teams data version %d", version)
+	}
+
+	for v := version; v < CurrentVersion; v++ {
+		if raw, err = upgrades[v](raw); err != nil {
+			return nil, 0, fmt.Errorf("upgrading teams data from version %d: %w", v, err)
+		}
+	}
+
+	var teams []Team
+	if err := json.Unmarshal(raw, &teams); err != nil {
+		return nil, 0, err
+	}
+	return teams, version, nil
+}
+
+// Upgrade rewrites teams data in the current version. It returns false with the data
+// unchanged if it is already current.
+func Upgrade(data []byte) ([]byte, bool, error) {
+	teams, version, err := Decode(data)
+	if err != nil {
+		return nil, false, err
+	}
+	if version == CurrentVersion {
+		return data, false, nil
+	}
+	upgraded, err := Encode(teams)
+	if err != nil {
+		return nil, false, err
+	}
+	return upgraded, true, nil
+}
+
+// parse splits stored data into its version and teams. Data saved before it was versioned
+// is a bare array of teams, which is version 1.
+func parse(data []byte) (int, json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		return 1, trimmed, nil
+	}
+
+	var doc document
+	if err := json.Unmarshal(trimmed, &doc); err != nil {
+		return 0, nil, err
+	}
+	if doc.Version < 2 || doc.Teams == nil {
+		return 0, nil, fmt.Errorf("invalid teams data version %d", doc.Version)
+	}
+	return doc.Version, doc.Teams, nil
+}
+
+// upgradeV1 keeps the snapshot fields of version 1 teams, whose players are full copies of
+// the player records at the time
+func upgradeV1(raw json.RawMessage) (json.RawMessage, error) {
+	var teams []struct {
+		Number  int `json:"number"`
+		Players []struct {
+			ID          uint    `json:"id"`
+			Name        string  `json:"name"`
+			SkillWeight float64 `json:"skill_weight"`
+			Position    string  `json:"position"`
+		} `json:"players"`
+		TotalWeight float64 `json:"total_weight"`
+	}
+	if err := json.Unmarshal(raw, &teams); err != nil {
+		return nil, err
+	}
+
+	type player struct {
+		ID          uint    `json:"id"`
+		Name        string  `json:"name"`
+		SkillWeight float64 `json:"skill_weight"`
+		Position    string  `json:"position,omitempty"`
+	}
+	type team struct {
+		Number      int      `json:"number"`
+		TotalWeight float64  `json:"total_weight"`
+		Players     []player `json:"players"`
+	}
+
+	result := make([]team, 0, len(teams))
+	for _, t := range teams {
+		converted := team{Number: t.Number, TotalWeight: t.TotalWeight, Players: make([]player, 0, len(t.Players))}
+		for _, p := range t.Players {
+			converted.Players = append(converted.Players, player(p))
+		}
+		result = append(result, converted)
+	}
+	return json.Marshal(result)
+}
+
+// upgradeV2 records the weight each player was balanced with. Version 2 didn't store it, so
+// their skill weight is the closest known value.
+func upgradeV2(raw json.RawMessage) (json.RawMessage, error) {
+	var teams []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &teams); err != nil {
+		return nil, err
+	}
+	for _, t := range teams {
+		if t["players"] == nil {
+			continue
+		}
+		var players []map[string]json.RawMessage
+		if err := json.Unmarshal(t["players"], &players); err != nil {
+			return nil, err
+		}
+		for _, p := range players {
+			p["balance_weight"] = p["skill_weight"]
+		}
+		data, err := json.Marshal(players)
+		if err != nil {
+			return nil, err
+		}
+		t["players"] = data
+	}
+	return json.Marshal(teams)
+}
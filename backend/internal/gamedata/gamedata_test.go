@@ -0,0 +1,154 @@
+package gamedata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		want        []Team
+		wantVersion int
+		wantErr     bool
+	}{
+		{
+			name: "version 1 bare array of full player records",
+			data: `[{"number":1,"total_weight":5,"players":[{"id":1,"user_id":9,"name":"Ann","skill_weight":3,"position":"goalie","rating":1500,"created_at":"2024-01-01T00:00:00Z"},{"id":2,"name":"Bo","skill_weight":2}]},
+				{"number":2,"total_weight":4,"players":[{"id":3,"name":"Cy","skill_weight":4}]}]`,
+			want: []Team{
+				{Number: 1, TotalWeight: 5, Players: []Player{
+					{ID: 1, Name: "Ann", SkillWeight: 3, BalanceWeight: 3, Position: "goalie"},
+					{ID: 2, Name: "Bo", SkillWeight: 2, BalanceWeight: 2},
+				}},
+				{Number: 2, TotalWeight: 4, Players: []Player{{ID: 3, Name: "Cy", SkillWeight: 4, BalanceWeight: 4}}},
+			},
+			wantVersion: 1,
+		},
+		{
+			name:        "version 1 with surrounding whitespace",
+			data:        "\n  []",
+			want:        []Team{},
+			wantVersion: 1,
+		},
+		{
+			name: "version 2 takes balance weights from skill weights",
+			data: `{"version":2,"teams":[{"number":1,"total_weight":3.5,"players":[{"id":1,"name":"Ann","skill_weight":3.5,"position":"forward"}]}]}`,
+			want: []Team{
+				{Number: 1, TotalWeight: 3.5, Players: []Player{{ID: 1, Name: "Ann", SkillWeight: 3.5, BalanceWeight: 3.5, Position: "forward"}}},
+			},
+			wantVersion: 2,
+		},
+		{
+			name: "version 3 keeps balance weights",
+			data: `{"version":3,"teams":[{"number":1,"total_weight":4.2,"players":[{"id":1,"name":"Ann","skill_weight":3,"balance_weight":4.2}]}]}`,
+			want: []Team{
+				{Number: 1, TotalWeight: 4.2, Players: []Player{{ID: 1, Name: "Ann", SkillWeight: 3, BalanceWeight: 4.2}}},
+			},
+			wantVersion: 3,
+		},
+		{name: "newer version", data: `{"version":99,"teams":[]}`, wantErr: true},
+		{name: "versioned without teams", data: `{"version":2}`, wantErr: true},
+		{name: "invalid version", data: `{"version":1,"teams":[]}`, wantErr: true},
+		{name: "not JSON", data: `garbage`, wantErr: true},
+		{name: "empty", data: ``, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, version, err := Decode([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if version != tt.wantVersion {
+				t.Errorf("Decode() version = %d, want %d", version, tt.wantVersion)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		teams []Team
+		want  []Team
+	}{
+		{
+			name: "teams",
+			teams: []Team{
+				{Number: 1, TotalWeight: 7, Players: []Player{{ID: 1, Name: "Ann", SkillWeight: 3, BalanceWeight: 4, Position: "defense"}, {ID: 2, Name: "Bo", SkillWeight: 3, BalanceWeight: 3}}},
+				{Number: 2, TotalWeight: 6, Players: []Player{{ID: 3, Name: "Cy", SkillWeight: 6, BalanceWeight: 6}}},
+			},
+		},
+		{name: "nil", teams: nil, want: []Team{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Encode(tt.teams)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, version, err := Decode(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.want
+			if want == nil {
+				want = tt.teams
+			}
+			if version != CurrentVersion || !reflect.DeepEqual(got, want) {
+				t.Errorf("Decode(Encode()) = %+v at version %d, want %+v at version %d", got, version, want, CurrentVersion)
+			}
+		})
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantChanged bool
+	}{
+		{name: "version 1", data: `[{"number":1,"total_weight":2,"players":[{"id":1,"name":"Ann","skill_weight":2}]}]`, wantChanged: true},
+		{name: "version 2", data: `{"version":2,"teams":[{"number":1,"total_weight":2,"players":[{"id":1,"name":"Ann","skill_weight":2}]}]}`, wantChanged: true},
+		{name: "current version", data: `{"version":3,"teams":[{"number":1,"total_weight":2,"players":[{"id":1,"name":"Ann","skill_weight":2,"balance_weight":2}]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, _, err := Decode([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			upgraded, changed, err := Upgrade([]byte(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("Upgrade() changed = %v, want %v", changed, tt.wantChanged)
+			}
+			if !changed && string(upgraded) != tt.data {
+				t.Errorf("Upgrade() rewrote current data as %s", upgraded)
+			}
+
+			after, version, err := Decode(upgraded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != CurrentVersion || !reflect.DeepEqual(after, before) {
+				t.Errorf("upgraded data decodes to %+v at version %d, want %+v at version %d", after, version, before, CurrentVersion)
+			}
+
+			again, changed, err := Upgrade(upgraded)
+			if err != nil || changed || string(again) != string(upgraded) {
+				t.Errorf("upgrading twice changed the data: %s, %v", again, err)
+			}
+		})
+	}
+}
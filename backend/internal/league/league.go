@@ -0,0 +1,152 @@
+package league
+
+import (
+	"sort"
+)
+
+// Pairing is one match between two teams, identified by their numbers starting from 1
+type Pairing struct {
+	Home int
+	Away int
+}
+
+// RoundRobin returns the rounds of a schedule where each of numTeams teams plays every other
+// team legs times. With an odd number of teams, one team sits out each round. Home and away
+// are swapped on every other leg.
+func RoundRobin(numTeams, legs int) [][]Pairing {
+	if numTeams < 2 || legs < 1 {
+		return nil
+	}
+
+	// Circle method: fix the first team and rotate the rest. 0 is the bye when odd.
+	teams := make([]int, 0, numTeams+1)
+	for i := 1; i <= numTeams; i++ {
+		teams = append(teams, i)
+	}
+	if numTeams%2 == 1 {
+		teams = append(teams, 0)
+	}
+	n := len(teams)
+
+	var firstLeg [][]Pairing
+	for round := 0; round < n-1; round++ {
+		pairings := make([]Pairing, 0, n/2)
+		for i := 0; i < n/2; i++ {
+			home, away := teams[i], teams[n-1-i]
+			if home == 0 || away == 0 {
+				continue
+			}
+			// Alternate the fixed team between home and away
+			if i == 0 && round%2 == 1 {
+				home, away = away, home
+			}
+			pairings = append(pairings, Pairing{Home: home, Away: away})
+		}
+		firstLeg = append(firstLeg, pairings)
+
+		last := teams[n-1]
+		copy(teams[2:], teams[1:n-1])
+		teams[1] = last
+	}
+
+	rounds := make([][]Pairing, 0, len(firstLeg)*legs)
+	for leg := 0; leg < legs; leg++ {
+		for _, pairings := range firstLeg {
+			round := make([]Pairing, len(pairings))
+			for i, p := range pairings {
+				if leg%2 == 1 {
+					p.Home, p.Away = p.Away, p.Home
+				}
+				round[i] = p
+			}
+			rounds = append(rounds, round)
+		}
+	}
+	return rounds
+}
+
+// Result is the final score of a played match
+type Result struct {
+	Home      int
+	Away      int
+	HomeScore int
+	AwayScore int
+}
+
+// Points is how many standings points a win, tie and loss are worth
+type Points struct {
+	Win  int
+	Tie  int
+	Loss int
+}
+
+// Standing is one team's record in a season
+type Standing struct {
+	Team             int `json:"team"`
+	Played           int `json:"played"`
+	Wins             int `json:"wins"`
+	Losses           int `json:"losses"`
+	Ties             int `json:"ties"`
+	Points           int `json:"points"`
+	GoalsFor         int `json:"goals_for"`
+	GoalsAgainst     int `json:"goals_against"`
+	GoalDifferential int `json:"goal_differential"`
+}
+
+// Standings tallies results into a table for teams 1 to numTeams, ordered by points, then
+// goal differential, then goals scored
+func Standings(numTeams int, results []Result, points Points) []Standing {
+	table := make([]Standing, numTeams)
+	for i := range table {
+		table[i].Team = i + 1
+	}
+	valid := func(team int) bool {
+		return team >= 1 && team <= numTeams
+	}
+
+	for _, r := range results {
+		if !valid(r.Home) || !valid(r.Away) {
+			continue
+		}
+		home, away := &table[r.Home-1], &table[r.Away-1]
+		home.Played++
+		away.Played++
+		home.GoalsFor += r.HomeScore
+		home.GoalsAgainst += r.AwayScore
+		away.GoalsFor += r.AwayScore
+		away.GoalsAgainst += r.HomeScore
+
+		switch {
+		case r.HomeScore > r.AwayScore:
+			home.Wins++
+			away.Losses++
+		case r.HomeScore < r.AwayScore:
+			away.Wins++
+			home.Losses++
+		default:
+			home.Ties++
+			away.Ties++
+		}
+	}
+
+	for i := range table {
+		s := &table[i]
+		s.Points = s.Wins*points.Win + s.Ties*points.Tie + s.Losses*points.Loss
+		s.GoalDifferential = s.GoalsFor - s.GoalsAgainst
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		a, b := table[i], table[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.GoalDifferential != b.GoalDifferential {
+			return a.GoalDifferential > b.GoalDifferential
+		}
+		if a.GoalsFor != b.GoalsFor {
+			return a.GoalsFor > b.GoalsFor
+		}
+		return a.Team < b.Team
+	})
+	return table
+}
@@ -0,0 +1,126 @@
+package league
+
+import "testing"
+
+func TestRoundRobin(t *testing.T) {
+	tests := []struct {
+		teams, legs int
+		wantRounds  int
+	}{
+		{teams: 2, legs: 1, wantRounds: 1},
+		{teams: 3, legs: 1, wantRounds: 3},
+		{teams: 4, legs: 1, wantRounds: 3},
+		{teams: 5, legs: 2, wantRounds: 10},
+		{teams: 6, legs: 1, wantRounds: 5},
+		{teams: 8, legs: 3, wantRounds: 21},
+	}
+	for _, tt := range tests {
+		rounds := RoundRobin(tt.teams, tt.legs)
+		if len(rounds) != tt.wantRounds {
+			t.Errorf("RoundRobin(%d, %d) has %d rounds, want %d", tt.teams, tt.legs, len(rounds), tt.wantRounds)
+			continue
+		}
+
+		met := make(map[[2]int]int)
+		homeGames := make(map[[2]int]int)
+		for r, round := range rounds {
+			playing := make(map[int]bool)
+			for _, p := range round {
+				if p.Home < 1 || p.Home > tt.teams || p.Away < 1 || p.Away > tt.teams || p.Home == p.Away {
+					t.Fatalf("RoundRobin(%d, %d) round %d has invalid pairing %+v", tt.teams, tt.legs, r+1, p)
+				}
+				if playing[p.Home] || playing[p.Away] {
+					t.Errorf("RoundRobin(%d, %d) round %d has a team playing twice", tt.teams, tt.legs, r+1)
+				}
+				playing[p.Home], playing[p.Away] = true, true
+				a, b := p.Home, p.Away
+				if a > b {
+					a, b = b, a
+				}
+				met[[2]int{a, b}]++
+				homeGames[[2]int{p.Home, p.Away}]++
+			}
+		}
+
+		for a := 1; a <= tt.teams; a++ {
+			for b := a + 1; b <= tt.teams; b++ {
+				if met[[2]int{a, b}] != tt.legs {
+					t.Errorf("RoundRobin(%d, %d): teams %d and %d meet %d times, want %d", tt.teams, tt.legs, a, b, met[[2]int{a, b}], tt.legs)
+				}
+				if tt.legs%2 == 0 && homeGames[[2]int{a, b}] != homeGames[[2]int{b, a}] {
+					t.Errorf("RoundRobin(%d, %d): teams %d and %d don't share home games evenly", tt.teams, tt.legs, a, b)
+				}
+			}
+		}
+	}
+}
+
+func TestRoundRobinInvalid(t *testing.T) {
+	tests := []struct{ teams, legs int }{{1, 1}, {0, 1}, {4, 0}}
+	for _, tt := range tests {
+		if rounds := RoundRobin(tt.teams, tt.legs); rounds != nil {
+			t.Errorf("RoundRobin(%d, %d) = %v, want nil", tt.teams, tt.legs, rounds)
+		}
+	}
+}
+
+func TestStandings(t *testing.T) {
+	points := Points{Win: 2, Tie: 1, Loss: 0}
+	tests := []struct {
+		name    string
+		teams   int
+		results []Result
+		want    []Standing
+	}{
+		{
+			name:  "no results ranks by team",
+			teams: 3,
+			want:  []Standing{{Team: 1}, {Team: 2}, {Team: 3}},
+		},
+		{
+			name:  "points, then goal differential",
+			teams: 3,
+			results: []Result{
+				{Home: 1, Away: 2, HomeScore: 3, AwayScore: 1},
+				{Home: 2, Away: 3, HomeScore: 2, AwayScore: 2},
+				{Home: 3, Away: 1, HomeScore: 5, AwayScore: 0},
+			},
+			want: []Standing{
+				{Team: 3, Played: 2, Wins: 1, Ties: 1, Points: 3, GoalsFor: 7, GoalsAgainst: 2, GoalDifferential: 5},
+				{Team: 1, Played: 2, Wins: 1, Losses: 1, Points: 2, GoalsFor: 3, GoalsAgainst: 6, GoalDifferential: -3},
+				{Team: 2, Played: 2, Losses: 1, Ties: 1, Points: 1, GoalsFor: 3, GoalsAgainst: 5, GoalDifferential: -2},
+			},
+		},
+		{
+			name:  "goals scored breaks a tie on differential",
+			teams: 2,
+			results: []Result{
+				{Home: 1, Away: 2, HomeScore: 1, AwayScore: 1},
+				{Home: 2, Away: 1, HomeScore: 4, AwayScore: 4},
+			},
+			want: []Standing{
+				{Team: 1, Played: 2, Ties: 2, Points: 2, GoalsFor: 5, GoalsAgainst: 5},
+				{Team: 2, Played: 2, Ties: 2, Points: 2, GoalsFor: 5, GoalsAgainst: 5},
+			},
+		},
+		{
+			name:    "results for unknown teams are ignored",
+			teams:   2,
+			results: []Result{{Home: 1, Away: 3, HomeScore: 9, AwayScore: 0}},
+			want:    []Standing{{Team: 1}, {Team: 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Standings(tt.teams, tt.results, points)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Standings() = %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("place %d = %+v, want %+v", i+1, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package ledger
+
+import (
+	"math"
+	"sort"
+)
+
+// Split divides total cents between payers in proportion to their shares, so a share of 1 is
+// a full charge and 0 is free. Cents lost to rounding go to the payers with the largest
+// remainders, earliest first, so the charges always add up to the total. Returns all zeros
+// if no one has a share.
+func Split(total int, shares []float64) []int {
+	amounts := make([]int, len(shares))
+
+	sum := 0.0
+	for _, s := range shares {
+		sum += math.Max(s, 0)
+	}
+	if sum == 0 {
+		return amounts
+	}
+
+	remainders := make([]float64, len(shares))
+	allocated := 0
+	for i, s := range shares {
+		exact := float64(total) * math.Max(s, 0) / sum
+		amounts[i] = int(math.Floor(exact))
+		remainders[i] = exact - float64(amounts[i])
+		allocated += amounts[i]
+	}
+
+	order := make([]int, 0, len(shares))
+	for i, s := range shares {
+		if s > 0 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for i := 0; allocated < total && len(order) > 0; i = (i + 1) % len(order) {
+		amounts[order[i]]++
+		allocated++
+	}
+	return amounts
+}
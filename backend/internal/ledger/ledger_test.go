@@ -0,0 +1,61 @@
+package ledger
+
+import "testing"
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name   string
+		total  int
+		shares []float64
+		want   []int
+	}{
+		{name: "even", total: 1000, shares: []float64{1, 1, 1, 1}, want: []int{250, 250, 250, 250}},
+		{name: "remainder to the earliest", total: 1000, shares: []float64{1, 1, 1}, want: []int{334, 333, 333}},
+		{name: "half share", total: 900, shares: []float64{1, 1, 0.5}, want: []int{360, 360, 180}},
+		{name: "free player", total: 1001, shares: []float64{1, 0, 1}, want: []int{501, 0, 500}},
+		{name: "largest remainder first", total: 101, shares: []float64{0.5, 1, 1}, want: []int{20, 41, 40}},
+		{name: "nobody pays", total: 500, shares: []float64{0, 0}, want: []int{0, 0}},
+		{name: "negative shares are free", total: 500, shares: []float64{-1, 1}, want: []int{0, 500}},
+		{name: "no payers", total: 500, shares: nil, want: []int{}},
+		{name: "one cent", total: 1, shares: []float64{1, 1, 1}, want: []int{1, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Split(tt.total, tt.shares)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Split(%d, %v) = %v, want %v", tt.total, tt.shares, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Split(%d, %v) = %v, want %v", tt.total, tt.shares, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitSumsToTotal(t *testing.T) {
+	shareSets := [][]float64{
+		{1},
+		{1, 1, 1},
+		{1, 0.5, 0.5, 1, 1, 1, 1},
+		{0.3, 0.3, 0.3},
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0},
+		{0.25, 1, 0.75, 1, 0.1},
+	}
+	for _, shares := range shareSets {
+		for total := 0; total <= 2500; total += 7 {
+			got := Split(total, shares)
+			sum := 0
+			for i, amount := range got {
+				if amount < 0 || (shares[i] <= 0 && amount != 0) {
+					t.Fatalf("Split(%d, %v) = %v charges a free player or a negative amount", total, shares, got)
+				}
+				sum += amount
+			}
+			if sum != total {
+				t.Fatalf("Split(%d, %v) = %v sums to %d", total, shares, got, sum)
+			}
+		}
+	}
+}
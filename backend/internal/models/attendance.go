@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// RSVP statuses for a session
+const (
+	RSVPIn         = "in"
+	RSVPOut        = "out"
+	RSVPMaybe      = "maybe"
+	RSVPWaitlisted = "waitlisted" // Wanted in but the session was full
+)
+
+// Attendance is a player's RSVP for a session and whether they checked in at the rink
+type Attendance struct {
+	SessionID    uint       `gorm:"primaryKey" json:"session_id"`
+	PlayerID     uint       `gorm:"primaryKey" json:"player_id"`
+	Status       string     `gorm:"size:10;not null" json:"status"` // "in", "out", "maybe" or "waitlisted"
+	WaitlistedAt *time.Time `json:"waitlisted_at,omitempty"`        // When the player joined the waitlist
+	CheckedInAt  *time.Time `json:"checked_in_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Session event types
+const (
+	SessionEventWaitlisted = "waitlisted"
+	SessionEventPromoted   = "promoted"
+	SessionEventDropped    = "dropped"
+	SessionEventSubbedIn   = "subbed_in" // A spare was brought in to replace a player who dropped out
+)
+
+// SessionEvent records a change to who is playing in a session, such as a player being
+// promoted off the waitlist
+type SessionEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SessionID uint      `gorm:"not null;index" json:"session_id"`
+	PlayerID  uint      `gorm:"not null" json:"player_id"`
+	Type      string    `gorm:"size:20;not null" json:"type"` // "waitlisted", "promoted", "dropped" or "subbed_in"
+	Detail    string    `gorm:"size:255" json:"detail"`       // e.g. the priority rule a promotion used
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,18 +1,151 @@
 package models
 
 import (
+	"log"
 	"time"
+
+	"github.com/sticktoss/backend/internal/gamedata"
+	"gorm.io/gorm"
 )
 
 type Game struct {
-	ShareID         string    `gorm:"primaryKey;size:12" json:"share_id"`
-	UserID          uint      `json:"user_id"`
-	GroupID         uint      `json:"group_id"`
-	GroupName       string    `gorm:"size:255" json:"group_name"`
-	GroupLogo       []byte    `gorm:"type:bytea" json:"-"` // Denormalized logo for public access
-	LogoContentType string    `gorm:"size:50" json:"logo_content_type,omitempty"`
-	NumTeams        int       `json:"num_teams"`
-	UseJerseyColors bool      `json:"use_jersey_colors"`
-	TeamsData       []byte    `gorm:"type:jsonb" json:"teams_data"` // Stores the complete team assignments
-	CreatedAt       time.Time `json:"created_at"`
+	ShareID          string     `gorm:"primaryKey;size:12" json:"share_id"`
+	UserID           uint       `json:"user_id"`
+	GroupID          uint       `json:"group_id"`
+	OrganizationID   uint       `gorm:"not null;default:0;index" json:"organization_id"`
+	SessionID        *uint      `gorm:"index" json:"session_id,omitempty"` // The session the game was generated for, if any
+	GroupName        string     `gorm:"size:255" json:"group_name"`
+	GroupLogo        []byte     `gorm:"type:bytea" json:"-"` // Denormalized logo for public access
+	LogoContentType  string     `gorm:"size:50" json:"logo_content_type,omitempty"`
+	NumTeams         int        `json:"num_teams"`
+	UseJerseyColors  bool       `json:"use_jersey_colors"`
+	TeamsData        []byte     `gorm:"type:jsonb" json:"teams_data"`              // The teams as generated, versioned by the gamedata package
+	RotationData     []byte     `gorm:"type:jsonb" json:"rotation_data,omitempty"` // On-ice rotation when three or more teams share the ice
+	GenerationData   []byte     `gorm:"type:jsonb" json:"-"`                       // Request, strategy and roster the teams were generated from
+	Revision         int        `gorm:"not null;default:0" json:"revision"`        // Latest manual edit, 0 if the lineup is as generated
+	ResultRecordedAt *time.Time `json:"result_recorded_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+
+	Scores []GameScore `gorm:"foreignKey:GameShareID;references:ShareID" json:"scores,omitempty"`
+	Teams  []GameTeam  `gorm:"foreignKey:GameShareID;references:ShareID" json:"teams,omitempty"`
+}
+
+// GameScore is one team's final score in a game
+type GameScore struct {
+	GameShareID string `gorm:"primaryKey;size:12" json:"-"`
+	TeamNumber  int    `gorm:"primaryKey" json:"team_number"`
+	Score       int    `gorm:"not null" json:"score"`
+}
+
+// GameTeam is one team of a generated game
+type GameTeam struct {
+	ID          uint    `gorm:"primaryKey" json:"-"`
+	GameShareID string  `gorm:"size:12;not null;uniqueIndex:idx_game_teams_game_number" json:"-"`
+	Number      int     `gorm:"not null;uniqueIndex:idx_game_teams_game_number" json:"number"`
+	TotalWeight float64 `gorm:"not null" json:"total_weight"`
+
+	Players []GameTeamPlayer `gorm:"foreignKey:GameTeamID" json:"players"`
+}
+
+// GameTeamPlayer is a player on a game team, with their name, skill weight and position as
+// they were when the game was generated
+type GameTeamPlayer struct {
+	GameTeamID  uint    `gorm:"primaryKey" json:"-"`
+	PlayerID    uint    `gorm:"primaryKey;index" json:"id"`
+	Slot        int     `gorm:"not null" json:"-"` // Order on the team, from 0
+	Name        string  `gorm:"not null" json:"name"`
+	SkillWeight float64 `gorm:"not null" json:"skill_weight"`
+	// Weight the player was balanced with, which team totals are made of
+	BalanceWeight float64 `gorm:"not null;default:0" json:"-"`
+	Position      string  `gorm:"size:10" json:"position,omitempty"`
+}
+
+// Manual edits to a game's lineup
+const (
+	GameEditGenerate = "generate" // The lineup as generated, revision 0
+	GameEditSwap     = "swap"     // Two players on different teams trade places
+	GameEditMove     = "move"     // A player moves to another team
+)
+
+// GameRevision is a game's lineup after a manual edit. The lineup as generated stays in the
+// game's teams data.
+type GameRevision struct {
+	ID             uint      `gorm:"primaryKey" json:"-"`
+	GameShareID    string    `gorm:"size:12;not null;uniqueIndex:idx_game_revisions_game_number" json:"-"`
+	Number         int       `gorm:"not null;uniqueIndex:idx_game_revisions_game_number" json:"number"` // From 1
+	Edit           string    `gorm:"size:10;not null" json:"edit"`
+	PlayerID       uint      `gorm:"not null" json:"player_id,omitempty"`
+	OtherPlayerID  *uint     `json:"other_player_id,omitempty"`                       // The player swapped with
+	TeamNumber     int       `gorm:"not null;default:0" json:"team_number,omitempty"` // The team moved to
+	TeamsData      []byte    `gorm:"type:jsonb" json:"-"`                             // The game's teams after the edit, versioned like the game's
+	EditedByUserID uint      `gorm:"not null" json:"edited_by_user_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// NewGameTeam builds a game team from its stored teams data
+func NewGameTeam(shareID string, team gamedata.Team) GameTeam {
+	result := GameTeam{
+		GameShareID: shareID,
+		Number:      team.Number,
+		TotalWeight: team.TotalWeight,
+		Players:     make([]GameTeamPlayer, 0, len(team.Players)),
+	}
+	for i, p := range team.Players {
+		result.Players = append(result.Players, GameTeamPlayer{
+			PlayerID:      p.ID,
+			Slot:          i,
+			Name:          p.Name,
+			SkillWeight:   p.SkillWeight,
+			BalanceWeight: p.BalanceWeight,
+			Position:      p.Position,
+		})
+	}
+	return result
+}
+
+// Data returns the team in the form it is stored in teams data
+func (t GameTeam) Data() gamedata.Team {
+	team := gamedata.Team{Number: t.Number, TotalWeight: t.TotalWeight, Players: make([]gamedata.Player, 0, len(t.Players))}
+	for _, p := range t.Players {
+		team.Players = append(team.Players, gamedata.Player{
+			ID:            p.PlayerID,
+			Name:          p.Name,
+			SkillWeight:   p.SkillWeight,
+			BalanceWeight: p.BalanceWeight,
+			Position:      p.Position,
+		})
+	}
+	return team
+}
+
+// migrateGameTeams copies the lineups of games saved before game teams existed out of their
+// teams data. Games whose data can't be read are logged and skipped so they don't stop the
+// server from starting; they are tried again on the next start.
+func migrateGameTeams(db *gorm.DB) error {
+	var games []Game
+	skipped := 0
+	err := db.Select("share_id", "teams_data").
+		Where("NOT EXISTS (SELECT 1 FROM game_teams WHERE game_teams.game_share_id = games.share_id)").
+		FindInBatches(&games, 100, func(_ *gorm.DB, _ int) error {
+			var teams []GameTeam
+			for _, game := range games {
+				lineup, _, err := gamedata.Decode(game.TeamsData)
+				if err != nil {
+					log.Printf("Skipping teams of game %s: %v", game.ShareID, err)
+					skipped++
+					continue
+				}
+				for _, t := range lineup {
+					teams = append(teams, NewGameTeam(game.ShareID, t))
+				}
+			}
+			if len(teams) == 0 {
+				return nil
+			}
+			return db.Create(&teams).Error
+		}).Error
+	if skipped > 0 {
+		log.Printf("Games with unreadable teams data skipped: %d", skipped)
+	}
+	return err
 }
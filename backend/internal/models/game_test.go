@@ -0,0 +1,43 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sticktoss/backend/internal/gamedata"
+)
+
+func TestGameTeamData(t *testing.T) {
+	tests := []struct {
+		name string
+		team gamedata.Team
+	}{
+		{
+			name: "players keep their order",
+			team: gamedata.Team{Number: 2, TotalWeight: 7.5, Players: []gamedata.Player{
+				{ID: 4, Name: "Dee", SkillWeight: 4, BalanceWeight: 4.5, Position: "goalie"},
+				{ID: 1, Name: "Ann", SkillWeight: 3, BalanceWeight: 3},
+			}},
+		},
+		{
+			name: "no players",
+			team: gamedata.Team{Number: 1, Players: []gamedata.Player{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			team := NewGameTeam("abc", tt.team)
+			if team.GameShareID != "abc" || team.Number != tt.team.Number || team.TotalWeight != tt.team.TotalWeight {
+				t.Errorf("NewGameTeam() = %+v", team)
+			}
+			for i, p := range team.Players {
+				if p.Slot != i {
+					t.Errorf("player %d has slot %d, want %d", p.PlayerID, p.Slot, i)
+				}
+			}
+			if got := team.Data(); !reflect.DeepEqual(got, tt.team) {
+				t.Errorf("NewGameTeam().Data() = %+v, want %+v", got, tt.team)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// Roles a user can have in a group
+const (
+	GroupRoleOwner  = "owner"  // Full control, including deleting the group and managing members
+	GroupRoleAdmin  = "admin"  // Can manage players, sessions and games
+	GroupRoleViewer = "viewer" // Read-only access
+)
+
+var groupRoleRank = map[string]int{
+	GroupRoleViewer: 1,
+	GroupRoleAdmin:  2,
+	GroupRoleOwner:  3,
+}
+
+// RoleAtLeast reports whether role grants at least the access of minRole
+func RoleAtLeast(role, minRole string) bool {
+	return groupRoleRank[role] >= groupRoleRank[minRole] && groupRoleRank[role] > 0
+}
+
+// GroupMember gives a user access to a group with a role. Every group has exactly one owner.
+type GroupMember struct {
+	GroupID   uint      `gorm:"primaryKey" json:"group_id"`
+	UserID    uint      `gorm:"primaryKey;index" json:"user_id"`
+	Role      string    `gorm:"size:10;not null" json:"role"` // "owner", "admin" or "viewer"
+	CreatedAt time.Time `json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// GroupInvite lets someone join a group with a role by following a link. If Email is set,
+// only the user with that email can accept it.
+type GroupInvite struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	GroupID         uint       `gorm:"not null;index" json:"group_id"`
+	Email           string     `gorm:"size:255" json:"email,omitempty"`
+	Role            string     `gorm:"size:10;not null" json:"role"` // "admin" or "viewer"
+	Token           string     `gorm:"uniqueIndex;size:32;not null" json:"token"`
+	CreatedByUserID uint       `gorm:"not null" json:"created_by_user_id"`
+	ExpiresAt       time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt      *time.Time `json:"accepted_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// PlayerInvite lets another user claim a player, linking the roster entry to their account
+type PlayerInvite struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	PlayerID        uint       `gorm:"not null;index" json:"player_id"`
+	Token           string     `gorm:"uniqueIndex;size:32;not null" json:"token"`
+	CreatedByUserID uint       `gorm:"not null" json:"created_by_user_id"`
+	ExpiresAt       time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt      *time.Time `json:"accepted_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
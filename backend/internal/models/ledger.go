@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+// Ledger entry kinds
+const (
+	LedgerCharge  = "charge"  // A player's share of a session's cost
+	LedgerPayment = "payment" // Money a player paid towards their balance
+)
+
+// LedgerEntry is one charge or payment in a group's dues ledger. Amounts are in cents and
+// always positive, the kind says which way they count.
+type LedgerEntry struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	GroupID          uint      `gorm:"not null;index" json:"group_id"`
+	PlayerID         uint      `gorm:"not null;index" json:"player_id"`
+	SessionID        *uint     `gorm:"index" json:"session_id,omitempty"` // The session a charge is for, or a payment was made at
+	Kind             string    `gorm:"size:10;not null" json:"kind"`
+	AmountCents      int       `gorm:"not null" json:"amount_cents"`
+	Note             string    `gorm:"size:255" json:"note,omitempty"`
+	RecordedByUserID uint      `gorm:"not null" json:"recorded_by_user_id"`
+	CreatedAt        time.Time `json:"created_at"`
+}
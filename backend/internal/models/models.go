@@ -11,6 +11,7 @@ type User struct {
 	ID           uint      `gorm:"primaryKey" json:"id"`
 	Email        string    `gorm:"uniqueIndex;not null" json:"email"`
 	PasswordHash string    `gorm:"not null" json:"-"`
+	SkillScaleID *uint     `json:"skill_scale_id"` // Account default skill scale (nil = built-in 1-5)
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 
@@ -20,12 +21,16 @@ type User struct {
 
 // Player represents a hockey player with a skill weight
 type Player struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	UserID      uint      `gorm:"not null;index" json:"user_id"`
-	Name        string    `gorm:"not null" json:"name"`
-	SkillWeight int       `gorm:"not null;check:skill_weight >= 1 AND skill_weight <= 5" json:"skill_weight"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	UserID          uint      `gorm:"not null;index" json:"user_id"` // Account that created the player; its skill scale applies
+	OrganizationID  uint      `gorm:"not null;default:0;index" json:"organization_id"`
+	Name            string    `gorm:"not null" json:"name"`
+	SkillWeight     float64   `gorm:"not null" json:"skill_weight"`      // Validated against the owner's skill scale
+	Position        string    `gorm:"size:10" json:"position,omitempty"` // "forward", "defense" or "goalie" (optional)
+	Rating          float64   `gorm:"not null;default:0" json:"-"`       // Learned rating from game results (0 = no games yet)
+	ClaimedByUserID *uint     `gorm:"index" json:"claimed_by_user_id"`   // Account the player signed in with after accepting an invite
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 
 	User   User    `gorm:"foreignKey:UserID" json:"-"`
 	Groups []Group `gorm:"many2many:group_players;" json:"-"`
@@ -33,25 +38,108 @@ type Player struct {
 
 // Group represents a collection of players
 type Group struct {
-	ID              uint      `gorm:"primaryKey" json:"id"`
-	UserID          uint      `gorm:"not null;index" json:"user_id"`
-	Name            string    `gorm:"not null" json:"name"`
-	Logo            []byte    `gorm:"type:bytea" json:"-"` // Logo image data (optional)
-	LogoContentType string    `gorm:"size:50" json:"logo_content_type,omitempty"` // e.g., "image/png"
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	UserID            uint      `gorm:"not null;index" json:"user_id"`
+	OrganizationID    uint      `gorm:"not null;default:0;index" json:"organization_id"`
+	Name              string    `gorm:"not null" json:"name"`
+	Logo              []byte    `gorm:"type:bytea" json:"-"`                                          // Logo image data (optional)
+	LogoContentType   string    `gorm:"size:50" json:"logo_content_type,omitempty"`                   // e.g., "image/png"
+	TeamBalancing     string    `gorm:"size:20;not null;default:manual" json:"team_balancing"`        // "manual", "rating" or "blended"
+	RatingBlend       float64   `gorm:"not null;default:0.5" json:"rating_blend"`                     // Share of the learned rating when blended (0-1)
+	SkillScaleID      *uint     `json:"skill_scale_id"`                                               // Overrides the owner's skill scale for this group
+	WaitlistPriority  string    `gorm:"size:20;not null;default:first_come" json:"waitlist_priority"` // Who is promoted first when a full session opens up
+	LateCancelHours   int       `gorm:"not null;default:24" json:"late_cancel_hours"`                 // Dropping out this close to a session counts as a late cancel
+	NoShowPenaltyRate float64   `gorm:"not null;default:0" json:"no_show_penalty_rate"`               // No-show rate that sends a player to the back of waitlists (0 = off)
+	GoaliesFree       bool      `gorm:"not null;default:false" json:"goalies_free"`                   // Goalies aren't charged for ice
+	SpareShare        float64   `gorm:"not null;default:1" json:"spare_share"`                        // Share of a full charge spares pay (0-1)
+	RegularsAlwaysPay bool      `gorm:"not null;default:false" json:"regulars_always_pay"`            // Regulars are charged for sessions they miss
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
 
 	User    User     `gorm:"foreignKey:UserID" json:"-"`
 	Players []Player `gorm:"many2many:group_players;" json:"players,omitempty"`
+
+	SkillOverrides map[uint]float64 `gorm:"-" json:"skill_overrides,omitempty"` // Per-group skill weights keyed by player ID
+	Regulars       []uint           `gorm:"-" json:"regulars,omitempty"`        // IDs of the group's regular players
+	Spares         []uint           `gorm:"-" json:"spares,omitempty"`          // IDs of the group's spares
+	Role           string           `gorm:"-" json:"role,omitempty"`            // The requesting user's role in the group
 }
 
+// Team balancing modes for a group
+const (
+	TeamBalancingManual  = "manual"
+	TeamBalancingRating  = "rating"
+	TeamBalancingBlended = "blended"
+)
+
+// Waitlist priority rules for a group's full sessions
+const (
+	WaitlistFirstCome   = "first_come"
+	WaitlistRegulars    = "regulars"
+	WaitlistLottery     = "lottery"
+	WaitlistLeastRecent = "least_recent"
+)
+
+// Player positions
+const (
+	PositionForward = "forward"
+	PositionDefense = "defense"
+	PositionGoalie  = "goalie"
+)
+
 // GroupPlayer is the junction table for the many-to-many relationship
 type GroupPlayer struct {
-	GroupID  uint `gorm:"primaryKey" json:"group_id"`
-	PlayerID uint `gorm:"primaryKey" json:"player_id"`
+	GroupID     uint     `gorm:"primaryKey" json:"group_id"`
+	PlayerID    uint     `gorm:"primaryKey" json:"player_id"`
+	SkillWeight *float64 `json:"skill_weight"`                             // Overrides the player's global weight in this group (nil = use global)
+	IsRegular   bool     `gorm:"not null;default:false" json:"is_regular"` // Regulars can be promoted off a waitlist first
+	IsSpare     bool     `gorm:"not null;default:false" json:"is_spare"`   // Spares only play when they are in for a session, usually as a sub
 }
 
 // Migrate runs database migrations
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(&User{}, &Player{}, &Group{}, &GroupPlayer{}, &Game{})
+	// Use GroupPlayer for the group/player association so its extra columns are kept
+	if err := db.SetupJoinTable(&Group{}, "Players", &GroupPlayer{}); err != nil {
+		return err
+	}
+	if err := db.SetupJoinTable(&Player{}, "Groups", &GroupPlayer{}); err != nil {
+		return err
+	}
+	if err := db.SetupJoinTable(&SeasonTeam{}, "Players", &SeasonTeamPlayer{}); err != nil {
+		return err
+	}
+
+	// Skill weights used to be limited to 1-5 by a check constraint; they are now
+	// validated against the configurable skill scale instead
+	if db.Migrator().HasTable(&Player{}) && db.Migrator().HasConstraint(&Player{}, "chk_players_skill_weight") {
+		if err := db.Migrator().DropConstraint(&Player{}, "chk_players_skill_weight"); err != nil {
+			return err
+		}
+	}
+
+	// Game team players saved before balance weights were recorded were balanced on their
+	// skill weights, as far as is known
+	backfillBalanceWeights := db.Migrator().HasTable(&GameTeamPlayer{}) && !db.Migrator().HasColumn(&GameTeamPlayer{}, "BalanceWeight")
+
+	if err := db.AutoMigrate(&User{}, &Player{}, &Group{}, &GroupPlayer{}, &Game{}, &GameScore{}, &GameTeam{}, &GameTeamPlayer{}, &GameRevision{}, &PlayerRating{}, &SkillScale{}, &SkillWeightChange{}, &Session{}, &SessionSchedule{}, &SessionScheduleException{}, &Attendance{}, &SessionEvent{}, &PlayerInvite{}, &GroupMember{}, &GroupInvite{}, &Organization{}, &OrganizationMember{}, &Season{}, &SeasonTeam{}, &SeasonTeamPlayer{}, &SeasonMatch{}, &Tournament{}, &TournamentTeam{}, &TournamentMatch{}, &LedgerEntry{}); err != nil {
+		return err
+	}
+
+	// Groups created before memberships existed are owned by the user who created them
+	if err := db.Exec(`INSERT INTO group_members (group_id, user_id, role, created_at)
+		SELECT id, user_id, ?, created_at FROM groups
+		WHERE NOT EXISTS (SELECT 1 FROM group_members WHERE group_members.group_id = groups.id)`, GroupRoleOwner).Error; err != nil {
+		return err
+	}
+
+	if backfillBalanceWeights {
+		if err := db.Exec("UPDATE game_team_players SET balance_weight = skill_weight").Error; err != nil {
+			return err
+		}
+	}
+	if err := migrateGameTeams(db); err != nil {
+		return err
+	}
+
+	return migrateOrganizations(db)
 }
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Organization is a tenant that owns players and groups, such as a league run by several
+// organizers. Every user has a personal organization for the players and groups they
+// create on their own.
+type Organization struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Personal  bool      `gorm:"not null;default:false" json:"personal"` // Created automatically for a single user
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Role string `gorm:"-" json:"role,omitempty"` // The requesting user's role in the organization
+}
+
+// OrganizationMember gives a user access to everything an organization owns. Organizations
+// use the same roles as groups.
+type OrganizationMember struct {
+	OrganizationID uint      `gorm:"primaryKey" json:"organization_id"`
+	UserID         uint      `gorm:"primaryKey;index" json:"user_id"`
+	Role           string    `gorm:"size:10;not null" json:"role"` // "owner", "admin" or "viewer"
+	CreatedAt      time.Time `json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// CreatePersonalOrganization creates a user's personal organization with them as its owner
+func CreatePersonalOrganization(tx *gorm.DB, userID uint) (Organization, error) {
+	org := Organization{Name: "Personal", Personal: true}
+	if err := tx.Create(&org).Error; err != nil {
+		return Organization{}, err
+	}
+	member := OrganizationMember{OrganizationID: org.ID, UserID: userID, Role: GroupRoleOwner}
+	if err := tx.Create(&member).Error; err != nil {
+		return Organization{}, err
+	}
+	return org, nil
+}
+
+// PersonalOrganizationID returns the ID of a user's personal organization
+func PersonalOrganizationID(tx *gorm.DB, userID uint) (uint, error) {
+	var org Organization
+	err := tx.Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organizations.personal = ? AND organization_members.user_id = ?", true, userID).
+		First(&org).Error
+	return org.ID, err
+}
+
+// migrateOrganizations gives every user without one a personal organization and moves the
+// players, groups and games they owned before organizations existed into it
+func migrateOrganizations(db *gorm.DB) error {
+	var userIDs []uint
+	if err := db.Model(&User{}).
+		Where("NOT EXISTS (SELECT 1 FROM organization_members JOIN organizations ON organizations.id = organization_members.organization_id WHERE organizations.personal = ? AND organization_members.user_id = users.id)", true).
+		Pluck("id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, userID := range userIDs {
+			if _, err := CreatePersonalOrganization(tx, userID); err != nil {
+				return err
+			}
+		}
+
+		personalOrg := `(SELECT organizations.id FROM organizations
+			JOIN organization_members ON organization_members.organization_id = organizations.id
+			WHERE organizations.personal = ? AND organization_members.user_id = %s.user_id)`
+		for _, table := range []string{"players", "groups", "games"} {
+			if err := tx.Exec(`UPDATE `+table+` SET organization_id = `+fmt.Sprintf(personalOrg, table)+
+				` WHERE organization_id = 0`, true).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// PlayerRating is one entry in a player's rating history, produced by a recorded game
+type PlayerRating struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	PlayerID     uint      `gorm:"not null;index" json:"player_id"`
+	GameShareID  string    `gorm:"size:12;not null;index" json:"game_share_id"`
+	RatingBefore float64   `json:"rating_before"`
+	RatingAfter  float64   `json:"rating_after"`
+	PlayedAt     time.Time `json:"played_at"` // When the game was generated
+	CreatedAt    time.Time `json:"created_at"`
+}
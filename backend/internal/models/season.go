@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+)
+
+// Season is a short league run within a group, with fixed teams drafted at the start
+type Season struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	GroupID    uint      `gorm:"not null;index" json:"group_id"`
+	Name       string    `gorm:"not null" json:"name"`
+	NumTeams   int       `gorm:"not null" json:"num_teams"`
+	PointsWin  int       `gorm:"not null;default:2" json:"points_win"` // Standings points for a win
+	PointsTie  int       `gorm:"not null;default:1" json:"points_tie"`
+	PointsLoss int       `gorm:"not null;default:0" json:"points_loss"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	Teams   []SeasonTeam  `gorm:"foreignKey:SeasonID" json:"teams,omitempty"`
+	Matches []SeasonMatch `gorm:"foreignKey:SeasonID" json:"matches,omitempty"`
+}
+
+// SeasonTeam is one of a season's fixed teams
+type SeasonTeam struct {
+	ID       uint   `gorm:"primaryKey" json:"id"`
+	SeasonID uint   `gorm:"not null;index" json:"season_id"`
+	Number   int    `gorm:"not null" json:"number"` // From 1, in draft order
+	Name     string `gorm:"not null" json:"name"`
+
+	Players []Player `gorm:"many2many:season_team_players;" json:"players,omitempty"`
+}
+
+// SeasonTeamPlayer is the junction table between season teams and their players
+type SeasonTeamPlayer struct {
+	SeasonTeamID uint `gorm:"primaryKey" json:"season_team_id"`
+	PlayerID     uint `gorm:"primaryKey;index" json:"player_id"`
+}
+
+// SeasonMatch is one scheduled game between two of a season's teams
+type SeasonMatch struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	SeasonID         uint       `gorm:"not null;index" json:"season_id"`
+	Round            int        `gorm:"not null" json:"round"`             // From 1
+	SessionID        *uint      `gorm:"index" json:"session_id,omitempty"` // The session the match is played in, if one was available
+	HomeTeamID       uint       `gorm:"not null" json:"home_team_id"`
+	AwayTeamID       uint       `gorm:"not null" json:"away_team_id"`
+	HomeScore        *int       `json:"home_score"`
+	AwayScore        *int       `json:"away_score"`
+	ResultRecordedAt *time.Time `json:"result_recorded_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// Session is a scheduled ice time for a group
+type Session struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	GroupID        uint      `gorm:"not null;index" json:"group_id"`
+	StartsAt       time.Time `gorm:"not null;index" json:"starts_at"`
+	EndsAt         time.Time `gorm:"not null" json:"ends_at"`
+	Venue          string    `gorm:"size:255" json:"venue"`
+	Capacity       int       `gorm:"not null;default:0" json:"capacity"`                                          // Maximum players, 0 = unlimited
+	CostCents      int       `gorm:"not null;default:0" json:"cost_cents"`                                        // Total ice cost, split between attendees
+	ScheduleID     *uint     `gorm:"uniqueIndex:idx_session_occurrence" json:"schedule_id,omitempty"`             // Set for sessions materialized from a schedule
+	OccurrenceDate string    `gorm:"size:10;uniqueIndex:idx_session_occurrence" json:"occurrence_date,omitempty"` // Schedule date this session was materialized for
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	Group Group `gorm:"foreignKey:GroupID" json:"-"`
+}
+
+// SessionSchedule is a recurring ice time that materializes upcoming sessions
+type SessionSchedule struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	GroupID         uint      `gorm:"not null;index" json:"group_id"`
+	Frequency       string    `gorm:"size:20;not null" json:"frequency"`  // "weekly", "biweekly" or "custom"
+	Rule            string    `gorm:"size:255;not null" json:"rule"`      // RRULE subset, e.g. "FREQ=WEEKLY;INTERVAL=1;BYDAY=TU"
+	StartDate       string    `gorm:"size:10;not null" json:"start_date"` // First possible date (YYYY-MM-DD)
+	StartTime       string    `gorm:"size:5;not null" json:"start_time"`  // Local start time (HH:MM)
+	DurationMinutes int       `gorm:"not null" json:"duration_minutes"`
+	Timezone        string    `gorm:"size:64;not null" json:"timezone"` // IANA name, e.g. "America/Toronto"
+	Venue           string    `gorm:"size:255" json:"venue"`
+	Capacity        int       `gorm:"not null;default:0" json:"capacity"`
+	CostCents       int       `gorm:"not null;default:0" json:"cost_cents"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	Exceptions []SessionScheduleException `gorm:"foreignKey:ScheduleID" json:"exceptions"`
+}
+
+// SessionScheduleException cancels a single date of a recurring schedule
+type SessionScheduleException struct {
+	ScheduleID uint   `gorm:"primaryKey" json:"-"`
+	Date       string `gorm:"primaryKey;size:10" json:"date"` // YYYY-MM-DD in the schedule's timezone
+}
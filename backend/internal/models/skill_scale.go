@@ -0,0 +1,60 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// SkillScale defines the range, granularity and level labels that skill weights are set on
+type SkillScale struct {
+	ID        uint         `gorm:"primaryKey" json:"id"`
+	UserID    uint         `gorm:"not null;index" json:"user_id"`
+	Name      string       `gorm:"not null" json:"name"`
+	Min       float64      `gorm:"not null" json:"min"`
+	Max       float64      `gorm:"not null" json:"max"`
+	Step      float64      `gorm:"not null" json:"step"`
+	Labels    []SkillLabel `gorm:"serializer:json" json:"labels"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// SkillLabel names one level of a skill scale (e.g. 1 = "Bender")
+type SkillLabel struct {
+	Value float64 `json:"value"`
+	Label string  `json:"label"`
+}
+
+// DefaultSkillScale is used when neither a group nor its owner has configured a scale
+var DefaultSkillScale = SkillScale{
+	Name: "Default",
+	Min:  1,
+	Max:  5,
+	Step: 1,
+	Labels: []SkillLabel{
+		{Value: 1, Label: "Bender"},
+		{Value: 2, Label: "Pylon"},
+		{Value: 3, Label: "Solid"},
+		{Value: 4, Label: "Stud"},
+		{Value: 5, Label: "Ringer"},
+	},
+}
+
+// Contains reports whether a weight is within the scale and lands on one of its steps
+func (s SkillScale) Contains(weight float64) bool {
+	const epsilon = 1e-9
+	if weight < s.Min-epsilon || weight > s.Max+epsilon {
+		return false
+	}
+	steps := (weight - s.Min) / s.Step
+	return math.Abs(steps-math.Round(steps)) < epsilon
+}
+
+// Normalize maps a weight on this scale onto the equivalent position on the default 1-5 scale
+func (s SkillScale) Normalize(weight float64) float64 {
+	return DefaultSkillScale.Min + (weight-s.Min)/(s.Max-s.Min)*(DefaultSkillScale.Max-DefaultSkillScale.Min)
+}
+
+// Denormalize maps a weight on the default 1-5 scale onto this scale
+func (s SkillScale) Denormalize(weight float64) float64 {
+	return s.Min + (weight-DefaultSkillScale.Min)/(DefaultSkillScale.Max-DefaultSkillScale.Min)*(s.Max-s.Min)
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// Tournament bracket formats
+const (
+	TournamentBracketNone   = "none" // Pool play only
+	TournamentBracketSingle = "single"
+	TournamentBracketDouble = "double"
+)
+
+// Tournament is a one-off competition between the teams of a generated game, played as
+// round-robin pools, an elimination bracket, or pools feeding a bracket
+type Tournament struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	GroupID        uint      `gorm:"not null;index" json:"group_id"`
+	GameShareID    string    `gorm:"size:12;not null;index" json:"game_share_id"` // The game whose teams take part
+	Name           string    `gorm:"not null" json:"name"`
+	Pools          int       `gorm:"not null;default:0" json:"pools"`            // 0 to go straight to the bracket
+	AdvancePerPool int       `gorm:"not null;default:0" json:"advance_per_pool"` // Teams from each pool that go on to the bracket
+	Bracket        string    `gorm:"size:10;not null" json:"bracket"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	Teams   []TournamentTeam  `gorm:"foreignKey:TournamentID" json:"teams,omitempty"`
+	Matches []TournamentMatch `gorm:"foreignKey:TournamentID" json:"matches,omitempty"`
+}
+
+// TournamentTeam is one of the game's teams, seeded by strength
+type TournamentTeam struct {
+	ID           uint    `gorm:"primaryKey" json:"id"`
+	TournamentID uint    `gorm:"not null;index" json:"tournament_id"`
+	Seed         int     `gorm:"not null" json:"seed"`        // From 1, strongest first
+	TeamNumber   int     `gorm:"not null" json:"team_number"` // The team's number in the game
+	Name         string  `gorm:"not null" json:"name"`
+	Strength     float64 `gorm:"not null" json:"strength"` // The team's total skill weight
+	Pool         int     `gorm:"not null;default:0" json:"pool"`
+}
+
+// TournamentMatch is one pool or bracket match. Teams are referred to by seed, 0 until known.
+type TournamentMatch struct {
+	ID               uint       `gorm:"primaryKey" json:"id"`
+	TournamentID     uint       `gorm:"not null;index" json:"tournament_id"`
+	Number           int        `gorm:"not null" json:"number"` // From 1, in creation order
+	Bracket          string     `gorm:"size:10;not null" json:"bracket"`
+	Pool             int        `gorm:"not null;default:0" json:"pool,omitempty"`
+	Round            int        `gorm:"not null" json:"round"`
+	HomeSeed         int        `gorm:"not null;default:0" json:"home_seed"`
+	AwaySeed         int        `gorm:"not null;default:0" json:"away_seed"`
+	HomeBye          bool       `gorm:"not null;default:false" json:"home_bye"`
+	AwayBye          bool       `gorm:"not null;default:false" json:"away_bye"`
+	HomeScore        *int       `json:"home_score"`
+	AwayScore        *int       `json:"away_score"`
+	WinnerSeed       int        `gorm:"not null;default:0" json:"winner_seed"`
+	Completed        bool       `gorm:"not null;default:false" json:"completed"`
+	Skipped          bool       `gorm:"not null;default:false" json:"skipped"`                // Decided without being played
+	WinnerToNumber   int        `gorm:"not null;default:0" json:"winner_to_number,omitempty"` // The match the winner moves on to
+	WinnerToSide     int        `gorm:"not null;default:0" json:"winner_to_side"`
+	LoserToNumber    int        `gorm:"not null;default:0" json:"loser_to_number,omitempty"` // The match the loser drops to in a double elimination
+	LoserToSide      int        `gorm:"not null;default:0" json:"loser_to_side"`
+	ResultRecordedAt *time.Time `json:"result_recorded_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
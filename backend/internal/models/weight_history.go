@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// Sources of a skill weight change
+const (
+	WeightSourceManual     = "manual"
+	WeightSourceSuggestion = "suggestion"
+)
+
+// SkillWeightChange records one change to a player's global skill weight or to their
+// weight override in a group
+type SkillWeightChange struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	PlayerID        uint      `gorm:"not null;index" json:"player_id"`
+	GroupID         *uint     `gorm:"index" json:"group_id"` // Set when a group override changed, nil for the global weight
+	OldWeight       *float64  `json:"old_weight"`            // nil when the player or override was created
+	NewWeight       *float64  `json:"new_weight"`            // nil when a group override was cleared
+	ChangedByUserID uint      `gorm:"not null" json:"changed_by_user_id"`
+	Source          string    `gorm:"size:20;not null" json:"source"` // "manual" or "suggestion"
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,98 @@
+package rating
+
+import (
+	"math"
+)
+
+const (
+	// BaseRating is the rating of an average (weight 3) player
+	BaseRating = 1500.0
+	// PointsPerWeight is how many rating points one skill weight level is worth
+	PointsPerWeight = 100.0
+	// KFactor controls how far a single game can move a rating
+	KFactor = 32.0
+	// baseWeight is the skill weight that maps to BaseRating
+	baseWeight = 3.0
+)
+
+// TeamResult is one team's lineup and final score in a recorded game
+type TeamResult struct {
+	PlayerIDs []uint
+	Score     int
+}
+
+// Seed returns the starting rating for a player with the given manual skill weight (on the 1-5 scale)
+func Seed(skillWeight float64) float64 {
+	return BaseRating + (skillWeight-baseWeight)*PointsPerWeight
+}
+
+// ToWeight converts a rating back onto the 1-5 skill weight scale so it can be used by teamgen
+func ToWeight(rating float64) float64 {
+	return baseWeight + (rating-BaseRating)/PointsPerWeight
+}
+
+// Expected returns the expected score (0-1) of a team rated a against a team rated b
+func Expected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// Update applies one game's results to the given ratings and returns the new rating for
+// every player in the game. Each team is compared against every other team; the team's
+// average rating is used for the expectation and the whole team moves by the same amount.
+// Wins by larger margins move ratings further. Players missing from ratings are treated
+// as BaseRating.
+func Update(ratings map[uint]float64, teams []TeamResult) map[uint]float64 {
+	averages := make([]float64, len(teams))
+	for i, team := range teams {
+		averages[i] = teamAverage(ratings, team.PlayerIDs)
+	}
+
+	updated := make(map[uint]float64)
+	for i, team := range teams {
+		delta := 0.0
+		for j, other := range teams {
+			if i == j {
+				continue
+			}
+
+			actual := actualScore(team.Score, other.Score)
+			delta += KFactor * marginMultiplier(team.Score-other.Score) * (actual - Expected(averages[i], averages[j]))
+		}
+		if len(teams) > 1 {
+			delta /= float64(len(teams) - 1)
+		}
+
+		for _, playerID := range team.PlayerIDs {
+			updated[playerID] = current(ratings, playerID) + delta
+		}
+	}
+
+	return updated
+}
+
+// marginMultiplier scales rating changes by goal differential (1 for ties, growing slowly with the margin)
+func marginMultiplier(margin int) float64 {
+	if margin < 0 {
+		margin = -margin
+	}
+	return 1 + math.Log1p(float64(margin))/2
+}
+
+func teamAverage(ratings map[uint]float64, playerIDs []uint) float64 {
+	if len(playerIDs) == 0 {
+		return BaseRating
+	}
+
+	total := 0.0
+	for _, playerID := range playerIDs {
+		total += current(ratings, playerID)
+	}
+	return total / float64(len(playerIDs))
+}
+
+func current(ratings map[uint]float64, playerID uint) float64 {
+	if r, ok := ratings[playerID]; ok {
+		return r
+	}
+	return BaseRating
+}
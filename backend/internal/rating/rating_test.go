@@ -0,0 +1,104 @@
+package rating
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSeedAndToWeight(t *testing.T) {
+	tests := []struct {
+		weight float64
+		want   float64
+	}{
+		{weight: 3, want: BaseRating},
+		{weight: 1, want: 1300},
+		{weight: 5, want: 1700},
+		{weight: 3.5, want: 1550},
+	}
+	for _, tt := range tests {
+		if got := Seed(tt.weight); got != tt.want {
+			t.Errorf("Seed(%v) = %v, want %v", tt.weight, got, tt.want)
+		}
+		if got := ToWeight(tt.want); got != tt.weight {
+			t.Errorf("ToWeight(%v) = %v, want %v", tt.want, got, tt.weight)
+		}
+	}
+}
+
+func TestExpected(t *testing.T) {
+	tests := []struct {
+		a, b float64
+		want float64
+	}{
+		{a: 1500, b: 1500, want: 0.5},
+		{a: 1900, b: 1500, want: 10.0 / 11},
+		{a: 1500, b: 1900, want: 1.0 / 11},
+	}
+	for _, tt := range tests {
+		if got := Expected(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Expected(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ratings map[uint]float64
+		teams   []TeamResult
+		want    map[uint]float64
+	}{
+		{
+			name:  "even teams tie",
+			teams: []TeamResult{{PlayerIDs: []uint{1, 2}, Score: 2}, {PlayerIDs: []uint{3}, Score: 2}},
+			want:  map[uint]float64{1: 1500, 2: 1500, 3: 1500},
+		},
+		{
+			name:  "one goal win between even teams",
+			teams: []TeamResult{{PlayerIDs: []uint{1}, Score: 1}, {PlayerIDs: []uint{2}, Score: 0}},
+			want: map[uint]float64{
+				1: 1500 + KFactor*0.5*(1+math.Log1p(1)/2),
+				2: 1500 - KFactor*0.5*(1+math.Log1p(1)/2),
+			},
+		},
+		{
+			name:    "teams use their average rating",
+			ratings: map[uint]float64{1: 1700, 2: 1300, 3: 1500},
+			teams:   []TeamResult{{PlayerIDs: []uint{1, 2}, Score: 3}, {PlayerIDs: []uint{3}, Score: 3}},
+			want:    map[uint]float64{1: 1700, 2: 1300, 3: 1500},
+		},
+		{
+			name:    "a tie against a stronger team gains",
+			ratings: map[uint]float64{1: 1300, 2: 1700},
+			teams:   []TeamResult{{PlayerIDs: []uint{1}, Score: 1}, {PlayerIDs: []uint{2}, Score: 1}},
+			want: map[uint]float64{
+				1: 1300 + KFactor*(0.5-Expected(1300, 1700)),
+				2: 1700 - KFactor*(0.5-Expected(1300, 1700)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Update(tt.ratings, tt.teams)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Update() = %v, want %v", got, tt.want)
+			}
+			for id, want := range tt.want {
+				if math.Abs(got[id]-want) > 1e-9 {
+					t.Errorf("player %d rating = %v, want %v", id, got[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateMargin(t *testing.T) {
+	narrow := Update(nil, []TeamResult{{PlayerIDs: []uint{1}, Score: 2}, {PlayerIDs: []uint{2}, Score: 1}})
+	wide := Update(nil, []TeamResult{{PlayerIDs: []uint{1}, Score: 8}, {PlayerIDs: []uint{2}, Score: 1}})
+	if wide[1] <= narrow[1] || wide[2] >= narrow[2] {
+		t.Errorf("a wide win moved ratings to %v, no further than a narrow one's %v", wide, narrow)
+	}
+	if math.Abs(wide[1]-BaseRating+wide[2]-BaseRating) > 1e-9 {
+		t.Errorf("ratings changed by %v and %v, want equal and opposite", wide[1]-BaseRating, wide[2]-BaseRating)
+	}
+}
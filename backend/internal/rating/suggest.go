@@ -0,0 +1,210 @@
+package rating
+
+import (
+	"math"
+)
+
+const (
+	// suggestThreshold is how far a player's average actual-minus-expected result must drift
+	// before a weight change is suggested
+	suggestThreshold = 0.15
+	// minSuggestGames is the fewest results needed before making any suggestion
+	minSuggestGames = 3
+	// weightEpsilon absorbs floating point error when comparing weights, as the skill scale does
+	weightEpsilon = 1e-9
+)
+
+// Confidence levels for a weight suggestion
+const (
+	ConfidenceLow    = "low"
+	ConfidenceMedium = "medium"
+	ConfidenceHigh   = "high"
+)
+
+// TeamOutcome is one team in a recorded game, with the weight it was balanced on.
+// TotalWeight must be on the default 1-5 scale so it can be turned into a rating.
+type TeamOutcome struct {
+	Players     []PlayerWeight
+	TotalWeight float64
+	Score       int
+}
+
+// PlayerWeight is a player's skill weight at the time a game was generated
+type PlayerWeight struct {
+	PlayerID uint
+	Weight   float64
+}
+
+// Suggestion is a recommended skill weight change for a player
+type Suggestion struct {
+	PlayerID        uint    `json:"player_id"`
+	CurrentWeight   float64 `json:"current_weight"`
+	SuggestedWeight float64 `json:"suggested_weight"`
+	GamesPlayed     int     `json:"games_played"`
+	Wins            int     `json:"wins"`
+	Losses          int     `json:"losses"`
+	Ties            int     `json:"ties"`
+	ExpectedScore   float64 `json:"expected_score"` // Average predicted result (0 = loss, 1 = win)
+	ActualScore     float64 `json:"actual_score"`   // Average actual result
+	Confidence      string  `json:"confidence"`
+}
+
+// Suggest compares the predicted outcome of each game (from the team weights at generation)
+// with the actual result and recommends moving a player's weight one step up or down when
+// their teams consistently beat or fall short of expectations. currentWeights holds the
+// weight of every player to consider; players without a clear trend are omitted. Games
+// played at a different weight than the current one are ignored, so accepting a
+// suggestion resets the evidence for that player.
+func Suggest(games [][]TeamOutcome, currentWeights map[uint]float64, step, minWeight, maxWeight float64) []Suggestion {
+	type tally struct {
+		residuals    []float64
+		expected     float64
+		actual       float64
+		wins, losses int
+		ties         int
+	}
+	tallies := make(map[uint]*tally)
+
+	for _, teams := range games {
+		for i, team := range teams {
+			expected, actual := 0.0, 0.0
+			for j, other := range teams {
+				if i == j {
+					continue
+				}
+				expected += Expected(strength(team), strength(other))
+				actual += actualScore(team.Score, other.Score)
+			}
+			if len(teams) > 1 {
+				expected /= float64(len(teams) - 1)
+				actual /= float64(len(teams) - 1)
+			}
+
+			for _, p := range team.Players {
+				if current, ok := currentWeights[p.PlayerID]; !ok || current != p.Weight {
+					continue
+				}
+				t := tallies[p.PlayerID]
+				if t == nil {
+					t = &tally{}
+					tallies[p.PlayerID] = t
+				}
+				t.residuals = append(t.residuals, actual-expected)
+				t.expected += expected
+				t.actual += actual
+				switch {
+				case actual > 0.5:
+					t.wins++
+				case actual < 0.5:
+					t.losses++
+				default:
+					t.ties++
+				}
+			}
+		}
+	}
+
+	suggestions := []Suggestion{}
+	for playerID, t := range tallies {
+		n := len(t.residuals)
+		if n < minSuggestGames {
+			continue
+		}
+
+		mean := (t.actual - t.expected) / float64(n)
+		if math.Abs(mean) < suggestThreshold {
+			continue
+		}
+
+		current := currentWeights[playerID]
+		suggested := current + step
+		if mean < 0 {
+			suggested = current - step
+		}
+		suggested = snapToStep(suggested, step, minWeight)
+		if suggested < minWeight-weightEpsilon || suggested > maxWeight+weightEpsilon {
+			continue
+		}
+
+		suggestions = append(suggestions, Suggestion{
+			PlayerID:        playerID,
+			CurrentWeight:   current,
+			SuggestedWeight: suggested,
+			GamesPlayed:     n,
+			Wins:            t.wins,
+			Losses:          t.losses,
+			Ties:            t.ties,
+			ExpectedScore:   round2(t.expected / float64(n)),
+			ActualScore:     round2(t.actual / float64(n)),
+			Confidence:      confidence(t.residuals, mean),
+		})
+	}
+
+	return suggestions
+}
+
+// ConfidenceRank orders confidence levels so callers can filter by a minimum
+func ConfidenceRank(level string) int {
+	switch level {
+	case ConfidenceHigh:
+		return 3
+	case ConfidenceMedium:
+		return 2
+	case ConfidenceLow:
+		return 1
+	}
+	return 0
+}
+
+// confidence grades how consistent a player's over/under-performance is, using a rough
+// t-statistic of the residuals
+func confidence(residuals []float64, mean float64) string {
+	n := float64(len(residuals))
+
+	variance := 0.0
+	for _, r := range residuals {
+		variance += (r - mean) * (r - mean)
+	}
+	if n > 1 {
+		variance /= n - 1
+	}
+	// Don't let a handful of identical results look like certainty
+	stddev := math.Max(math.Sqrt(variance), 0.25)
+
+	t := math.Abs(mean) / (stddev / math.Sqrt(n))
+	switch {
+	case n >= 8 && t >= 2.5:
+		return ConfidenceHigh
+	case n >= 5 && t >= 1.5:
+		return ConfidenceMedium
+	}
+	return ConfidenceLow
+}
+
+// strength converts a team's average weight into a rating for outcome prediction
+func strength(team TeamOutcome) float64 {
+	if len(team.Players) == 0 {
+		return BaseRating
+	}
+	return Seed(team.TotalWeight / float64(len(team.Players)))
+}
+
+func actualScore(score, otherScore int) float64 {
+	if score > otherScore {
+		return 1
+	} else if score < otherScore {
+		return 0
+	}
+	return 0.5
+}
+
+// snapToStep moves a weight onto the nearest step of a scale starting at minWeight, dropping
+// the floating point error that adding steps builds up
+func snapToStep(weight, step, minWeight float64) float64 {
+	snapped := minWeight + math.Round((weight-minWeight)/step)*step
+	return math.Round(snapped*1e9) / 1e9
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
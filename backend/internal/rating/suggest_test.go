@@ -0,0 +1,149 @@
+package rating
+
+import (
+	"reflect"
+	"testing"
+)
+
+// game builds a two-team game from player ID to weight maps
+func game(a map[uint]float64, scoreA int, b map[uint]float64, scoreB int) []TeamOutcome {
+	team := func(weights map[uint]float64, score int) TeamOutcome {
+		t := TeamOutcome{Score: score}
+		for id, w := range weights {
+			t.Players = append(t.Players, PlayerWeight{PlayerID: id, Weight: w})
+			t.TotalWeight += w
+		}
+		return t
+	}
+	return []TeamOutcome{team(a, scoreA), team(b, scoreB)}
+}
+
+// repeat returns n copies of a game
+func repeat(n int, g []TeamOutcome) [][]TeamOutcome {
+	games := make([][]TeamOutcome, n)
+	for i := range games {
+		games[i] = g
+	}
+	return games
+}
+
+func TestSuggest(t *testing.T) {
+	even := map[uint]float64{1: 3, 2: 3}
+	win := game(map[uint]float64{1: 3}, 3, map[uint]float64{2: 3}, 1)
+	loss := game(map[uint]float64{1: 3}, 0, map[uint]float64{2: 3}, 2)
+
+	tests := []struct {
+		name    string
+		games   [][]TeamOutcome
+		current map[uint]float64
+		step    float64
+		max     float64
+		want    map[uint]float64
+	}{
+		{
+			name:    "winner moves up and loser down",
+			games:   repeat(3, win),
+			current: even,
+			want:    map[uint]float64{1: 3.5, 2: 2.5},
+		},
+		{
+			name:    "too few games",
+			games:   repeat(2, win),
+			current: even,
+			want:    map[uint]float64{},
+		},
+		{
+			name:    "results as expected",
+			games:   append(repeat(2, win), repeat(2, loss)...),
+			current: even,
+			want:    map[uint]float64{},
+		},
+		{
+			name:    "games at an old weight are ignored",
+			games:   repeat(3, win),
+			current: map[uint]float64{1: 3.5, 2: 3},
+			want:    map[uint]float64{2: 2.5},
+		},
+		{
+			name:    "players not asked about are left out",
+			games:   repeat(3, win),
+			current: map[uint]float64{2: 3},
+			want:    map[uint]float64{2: 2.5},
+		},
+		{
+			name:    "no suggestion past the top of the scale",
+			games:   repeat(3, win),
+			current: even,
+			max:     3.2,
+			want:    map[uint]float64{2: 2.5},
+		},
+		{
+			name:    "steps snap to the scale",
+			games:   repeat(3, game(map[uint]float64{1: 2.9}, 3, map[uint]float64{2: 2.9}, 1)),
+			current: map[uint]float64{1: 2.9, 2: 2.9},
+			step:    0.1,
+			want:    map[uint]float64{1: 3, 2: 2.8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, max := tt.step, tt.max
+			if step == 0 {
+				step = 0.5
+			}
+			if max == 0 {
+				max = 5
+			}
+			got := map[uint]float64{}
+			for _, s := range Suggest(tt.games, tt.current, step, 1, max) {
+				got[s.PlayerID] = s.SuggestedWeight
+				if s.CurrentWeight != tt.current[s.PlayerID] {
+					t.Errorf("player %d current weight = %v, want %v", s.PlayerID, s.CurrentWeight, tt.current[s.PlayerID])
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestRecord(t *testing.T) {
+	games := append(repeat(3, game(map[uint]float64{1: 3}, 3, map[uint]float64{2: 3}, 1)),
+		game(map[uint]float64{1: 3}, 2, map[uint]float64{2: 3}, 2))
+
+	want := Suggestion{
+		PlayerID: 1, CurrentWeight: 3, SuggestedWeight: 3.5,
+		GamesPlayed: 4, Wins: 3, Ties: 1,
+		ExpectedScore: 0.5, ActualScore: 0.88, Confidence: ConfidenceLow,
+	}
+	if got := Suggest(games, map[uint]float64{1: 3}, 0.5, 1, 5); len(got) != 1 || got[0] != want {
+		t.Errorf("Suggest() = %+v, want [%+v]", got, want)
+	}
+}
+
+func TestSuggestConfidence(t *testing.T) {
+	win := game(map[uint]float64{1: 3}, 3, map[uint]float64{2: 3}, 1)
+	tests := []struct {
+		games int
+		want  string
+	}{
+		{games: 3, want: ConfidenceLow},
+		{games: 5, want: ConfidenceMedium},
+		{games: 8, want: ConfidenceHigh},
+	}
+	for _, tt := range tests {
+		got := Suggest(repeat(tt.games, win), map[uint]float64{1: 3}, 0.5, 1, 5)
+		if len(got) != 1 || got[0].Confidence != tt.want {
+			t.Errorf("%d wins: Suggest() = %+v, want %s confidence", tt.games, got, tt.want)
+		}
+	}
+}
+
+func TestConfidenceRank(t *testing.T) {
+	if !(ConfidenceRank(ConfidenceHigh) > ConfidenceRank(ConfidenceMedium) &&
+		ConfidenceRank(ConfidenceMedium) > ConfidenceRank(ConfidenceLow) &&
+		ConfidenceRank(ConfidenceLow) > ConfidenceRank("")) {
+		t.Error("ConfidenceRank() does not order high > medium > low > unknown")
+	}
+}
@@ -0,0 +1,145 @@
+package rotation
+
+import (
+	"errors"
+	"sort"
+)
+
+// Shift is one period of the rotation: two teams on the ice while the rest sit
+type Shift struct {
+	Period      int   `json:"period"`       // From 1
+	StartMinute int   `json:"start_minute"` // Minutes from the start of the session
+	EndMinute   int   `json:"end_minute"`
+	Home        int   `json:"home"` // Team numbers, from 1
+	Away        int   `json:"away"`
+	Sitting     []int `json:"sitting"`
+}
+
+// TeamIceTime is how much of the session a team spends on the ice
+type TeamIceTime struct {
+	Team        int `json:"team"`
+	Periods     int `json:"periods"`
+	Minutes     int `json:"minutes"`
+	LongestSit  int `json:"longest_sit"` // Most periods in a row spent sitting
+	Opponents   int `json:"opponents"`   // Different teams played against
+	HomePeriods int `json:"home_periods"`
+}
+
+// Rotation is an on-ice schedule for more teams than fit on the ice at once
+type Rotation struct {
+	NumTeams       int           `json:"num_teams"`
+	SessionMinutes int           `json:"session_minutes"`
+	PeriodMinutes  int           `json:"period_minutes"`
+	Shifts         []Shift       `json:"shifts"`
+	Teams          []TeamIceTime `json:"teams"`
+}
+
+// Schedule splits a session into periods of periodMinutes, leaving off any minutes that don't
+// make a full period, and picks the two teams on the ice each period. Ice time is kept within
+// one period between teams, teams that sat out go back on first so nobody sits twice in a row
+// while there are few enough teams to avoid it, and opponents are rotated as evenly as possible.
+func Schedule(numTeams, sessionMinutes, periodMinutes int) (Rotation, error) {
+	if numTeams < 3 {
+		return Rotation{}, errors.New("a rotation needs at least three teams")
+	}
+	if periodMinutes <= 0 || periodMinutes > sessionMinutes {
+		return Rotation{}, errors.New("period length must be positive and no longer than the session")
+	}
+
+	ice := make([]int, numTeams+1)
+	sitting := make([]int, numTeams+1) // Periods in a row each team has currently sat
+	home := make([]int, numTeams+1)
+	met := make([][]int, numTeams+1)
+	lastMet := make([][]int, numTeams+1)
+	for t := range met {
+		met[t] = make([]int, numTeams+1)
+		lastMet[t] = make([]int, numTeams+1)
+	}
+
+	teams := make([]TeamIceTime, numTeams)
+	for i := range teams {
+		teams[i].Team = i + 1
+	}
+
+	r := Rotation{NumTeams: numTeams, SessionMinutes: sessionMinutes, PeriodMinutes: periodMinutes}
+	for period := 1; period <= sessionMinutes/periodMinutes; period++ {
+		candidates := make([]int, numTeams)
+		for i := range candidates {
+			candidates[i] = i + 1
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			a, b := candidates[i], candidates[j]
+			if ice[a] != ice[b] {
+				return ice[a] < ice[b]
+			}
+			return sitting[a] > sitting[b]
+		})
+		first := candidates[0]
+
+		rest := candidates[1:]
+		sort.SliceStable(rest, func(i, j int) bool {
+			a, b := rest[i], rest[j]
+			if ice[a] != ice[b] {
+				return ice[a] < ice[b]
+			}
+			if sitting[a] != sitting[b] {
+				return sitting[a] > sitting[b]
+			}
+			if met[first][a] != met[first][b] {
+				return met[first][a] < met[first][b]
+			}
+			if lastMet[first][a] != lastMet[first][b] {
+				return lastMet[first][a] < lastMet[first][b]
+			}
+			return a < b
+		})
+		second := rest[0]
+
+		// Alternate who is home so it evens out
+		h, a := first, second
+		if home[a] < home[h] || (home[a] == home[h] && a < h) {
+			h, a = a, h
+		}
+
+		shift := Shift{
+			Period:      period,
+			StartMinute: (period - 1) * periodMinutes,
+			EndMinute:   period * periodMinutes,
+			Home:        h,
+			Away:        a,
+			Sitting:     []int{},
+		}
+		for t := 1; t <= numTeams; t++ {
+			if t == h || t == a {
+				ice[t]++
+				sitting[t] = 0
+				continue
+			}
+			sitting[t]++
+			shift.Sitting = append(shift.Sitting, t)
+			if sitting[t] > teams[t-1].LongestSit {
+				teams[t-1].LongestSit = sitting[t]
+			}
+		}
+		home[h]++
+		met[h][a]++
+		met[a][h]++
+		lastMet[h][a] = period
+		lastMet[a][h] = period
+		r.Shifts = append(r.Shifts, shift)
+	}
+
+	for i := range teams {
+		t := i + 1
+		teams[i].Periods = ice[t]
+		teams[i].Minutes = ice[t] * periodMinutes
+		teams[i].HomePeriods = home[t]
+		for o := 1; o <= numTeams; o++ {
+			if met[t][o] > 0 {
+				teams[i].Opponents++
+			}
+		}
+	}
+	r.Teams = teams
+	return r, nil
+}
@@ -0,0 +1,88 @@
+package rotation
+
+import "testing"
+
+func TestSchedule(t *testing.T) {
+	tests := []struct {
+		teams, sessionMinutes, periodMinutes int
+		wantPeriods                          int
+	}{
+		{teams: 3, sessionMinutes: 60, periodMinutes: 10, wantPeriods: 6},
+		{teams: 3, sessionMinutes: 65, periodMinutes: 10, wantPeriods: 6},
+		{teams: 4, sessionMinutes: 90, periodMinutes: 8, wantPeriods: 11},
+		{teams: 5, sessionMinutes: 90, periodMinutes: 6, wantPeriods: 15},
+		{teams: 6, sessionMinutes: 120, periodMinutes: 5, wantPeriods: 24},
+		{teams: 8, sessionMinutes: 60, periodMinutes: 60, wantPeriods: 1},
+	}
+	for _, tt := range tests {
+		r, err := Schedule(tt.teams, tt.sessionMinutes, tt.periodMinutes)
+		if err != nil {
+			t.Fatalf("Schedule(%d, %d, %d): %v", tt.teams, tt.sessionMinutes, tt.periodMinutes, err)
+		}
+		if len(r.Shifts) != tt.wantPeriods {
+			t.Errorf("Schedule(%d, %d, %d) has %d periods, want %d", tt.teams, tt.sessionMinutes, tt.periodMinutes, len(r.Shifts), tt.wantPeriods)
+			continue
+		}
+
+		sat := make([]int, tt.teams+1)
+		for i, s := range r.Shifts {
+			if s.StartMinute != i*tt.periodMinutes || s.EndMinute != (i+1)*tt.periodMinutes {
+				t.Errorf("Schedule(%d, %d, %d) period %d runs %d-%d", tt.teams, tt.sessionMinutes, tt.periodMinutes, s.Period, s.StartMinute, s.EndMinute)
+			}
+			if s.Home == s.Away || len(s.Sitting) != tt.teams-2 {
+				t.Errorf("Schedule(%d, %d, %d) period %d has invalid teams %+v", tt.teams, tt.sessionMinutes, tt.periodMinutes, s.Period, s)
+			}
+			for _, team := range s.Sitting {
+				if team == s.Home || team == s.Away {
+					t.Errorf("Schedule(%d, %d, %d) period %d has team %d both on and off the ice", tt.teams, tt.sessionMinutes, tt.periodMinutes, s.Period, team)
+				}
+				// With four teams or fewer nobody needs to sit twice in a row
+				if tt.teams <= 4 && i > 0 && sat[team] == i {
+					t.Errorf("Schedule(%d, %d, %d) has team %d sitting periods %d and %d", tt.teams, tt.sessionMinutes, tt.periodMinutes, team, i, i+1)
+				}
+				sat[team] = i + 1
+			}
+		}
+
+		least, most := r.Teams[0].Periods, r.Teams[0].Periods
+		for _, team := range r.Teams {
+			least = min(least, team.Periods)
+			most = max(most, team.Periods)
+			if team.Minutes != team.Periods*tt.periodMinutes {
+				t.Errorf("Schedule(%d, %d, %d) team %d has %d minutes for %d periods", tt.teams, tt.sessionMinutes, tt.periodMinutes, team.Team, team.Minutes, team.Periods)
+			}
+		}
+		if most-least > 1 {
+			t.Errorf("Schedule(%d, %d, %d) gives teams between %d and %d periods", tt.teams, tt.sessionMinutes, tt.periodMinutes, least, most)
+		}
+	}
+}
+
+func TestScheduleOpponents(t *testing.T) {
+	// Three periods of three teams is a full round-robin
+	r, err := Schedule(3, 30, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, team := range r.Teams {
+		if team.Opponents != 2 || team.Periods != 2 || team.LongestSit != 1 {
+			t.Errorf("team %d = %+v, want 2 opponents over 2 periods sitting once", team.Team, team)
+		}
+	}
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	tests := []struct {
+		name                                 string
+		teams, sessionMinutes, periodMinutes int
+	}{
+		{name: "two teams", teams: 2, sessionMinutes: 60, periodMinutes: 10},
+		{name: "zero period", teams: 3, sessionMinutes: 60, periodMinutes: 0},
+		{name: "period longer than session", teams: 3, sessionMinutes: 60, periodMinutes: 61},
+	}
+	for _, tt := range tests {
+		if _, err := Schedule(tt.teams, tt.sessionMinutes, tt.periodMinutes); err == nil {
+			t.Errorf("%s: Schedule(%d, %d, %d) succeeded, want an error", tt.name, tt.teams, tt.sessionMinutes, tt.periodMinutes)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the timezone database so schedules work on hosts without one
+	_ "time/tzdata"
+)
+
+// DateLayout is the format of calendar dates used by schedules
+const DateLayout = "2006-01-02"
+
+// maxSearchDays bounds how far past its starting point Occurrences looks, so sparse rules
+// can't loop forever
+const maxSearchDays = 366 * 5
+
+var dayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Rule is the supported subset of an iCalendar RRULE: weekly recurrence with an interval,
+// a set of weekdays and an optional end date or occurrence count
+type Rule struct {
+	Interval int            // Weeks between occurrences
+	ByDay    []time.Weekday // Days of the week, defaults to the start date's weekday
+	Until    string         // Last possible date (YYYY-MM-DD), inclusive
+	Count    int            // Maximum number of occurrences, 0 = unlimited
+}
+
+// ParseRule parses an RRULE such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;UNTIL=20250601".
+// Only FREQ=WEEKLY is supported.
+func ParseRule(s string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+
+	hasFreq := false
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return Rule{}, fmt.Errorf("invalid rule part %q", part)
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			if value != "WEEKLY" {
+				return Rule{}, errors.New("only weekly recurrence is supported")
+			}
+			hasFreq = true
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, errors.New("interval must be a positive number")
+			}
+			rule.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, ok := dayCodes[code]
+				if !ok {
+					return Rule{}, fmt.Errorf("invalid day %q", code)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "UNTIL":
+			// Accept both the RRULE date form and a time suffix, which is ignored
+			until, err := time.Parse("20060102", value[:min(len(value), 8)])
+			if err != nil {
+				return Rule{}, errors.New("invalid until date")
+			}
+			rule.Until = until.Format(DateLayout)
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return Rule{}, errors.New("count must be a positive number")
+			}
+			rule.Count = n
+		default:
+			return Rule{}, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+
+	if !hasFreq {
+		return Rule{}, errors.New("rule must include FREQ=WEEKLY")
+	}
+	return rule, nil
+}
+
+// String formats the rule as an RRULE
+func (r Rule) String() string {
+	parts := []string{"FREQ=WEEKLY", "INTERVAL=" + strconv.Itoa(r.Interval)}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			for code, d := range dayCodes {
+				if d == day {
+					codes = append(codes, code)
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Until != "" {
+		parts = append(parts, "UNTIL="+strings.ReplaceAll(r.Until, "-", ""))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Schedule is a recurring session time in a specific timezone
+type Schedule struct {
+	Rule       Rule
+	StartDate  string          // First possible date (YYYY-MM-DD)
+	StartTime  string          // Local start time (HH:MM)
+	Duration   time.Duration   // Length of each session
+	Location   *time.Location  // Timezone the dates and times are in
+	Exceptions map[string]bool // Cancelled dates (YYYY-MM-DD)
+}
+
+// Occurrence is one scheduled session
+type Occurrence struct {
+	Date     string    `json:"date"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+// Occurrences returns up to n occurrences that start at or after from, skipping cancelled
+// dates. Local times are kept across daylight saving changes.
+func (s Schedule) Occurrences(from time.Time, n int) ([]Occurrence, error) {
+	return s.occurrences(from, n, time.Time{})
+}
+
+// Between returns every occurrence starting in [from, to), skipping cancelled dates
+func (s Schedule) Between(from, to time.Time) ([]Occurrence, error) {
+	return s.occurrences(from, -1, to)
+}
+
+func (s Schedule) occurrences(from time.Time, n int, to time.Time) ([]Occurrence, error) {
+	start, err := time.ParseInLocation(DateLayout, s.StartDate, s.Location)
+	if err != nil {
+		return nil, errors.New("invalid start date")
+	}
+	hour, minute, err := parseClock(s.StartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var until time.Time
+	if s.Rule.Until != "" {
+		if until, err = time.ParseInLocation(DateLayout, s.Rule.Until, s.Location); err != nil {
+			return nil, errors.New("invalid until date")
+		}
+	}
+
+	days := s.Rule.ByDay
+	if len(days) == 0 {
+		days = []time.Weekday{start.Weekday()}
+	}
+	onDay := make(map[time.Weekday]bool, len(days))
+	for _, d := range days {
+		onDay[d] = true
+	}
+
+	interval := s.Rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	// Weeks are counted from the Sunday on or before the start date
+	firstWeek := start.AddDate(0, 0, -int(start.Weekday()))
+	matches := func(date time.Time) bool {
+		week := int(date.Sub(firstWeek).Hours()/24+0.5) / 7
+		return onDay[date.Weekday()] && week%interval == 0
+	}
+
+	// Scan from the later of the start date and from's date. Weeks are still counted from
+	// the start date, so skipping ahead keeps the interval; COUNT needs the occurrences
+	// skipped over counted first.
+	scan := start
+	local := from.In(s.Location)
+	if fromDate := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.Location); fromDate.After(start) {
+		scan = fromDate
+	}
+	count := 0
+	if s.Rule.Count > 0 {
+		for date := start; date.Before(scan) && count < s.Rule.Count; date = date.AddDate(0, 0, 1) {
+			if matches(date) {
+				count++
+			}
+		}
+	}
+
+	result := []Occurrence{}
+	for i := 0; i < maxSearchDays; i++ {
+		date := scan.AddDate(0, 0, i)
+		if !until.IsZero() && date.After(until) {
+			break
+		}
+		if !matches(date) {
+			continue
+		}
+
+		// COUNT includes cancelled dates, matching RRULE semantics where EXDATE removes
+		// occurrences without extending the series
+		count++
+		if s.Rule.Count > 0 && count > s.Rule.Count {
+			break
+		}
+
+		dateStr := date.Format(DateLayout)
+		if s.Exceptions[dateStr] {
+			continue
+		}
+
+		startsAt := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, s.Location)
+		if startsAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !startsAt.Before(to) {
+			break
+		}
+
+		result = append(result, Occurrence{
+			Date:     dateStr,
+			StartsAt: startsAt,
+			EndsAt:   startsAt.Add(s.Duration),
+		})
+		if n >= 0 && len(result) >= n {
+			break
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].StartsAt.Before(result[j].StartsAt)
+	})
+	return result, nil
+}
+
+// parseClock parses a local time of day in HH:MM form
+func parseClock(s string) (int, int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, 0, errors.New("invalid start time, expected HH:MM")
+	}
+	return t.Hour(), t.Minute(), nil
+}
@@ -0,0 +1,226 @@
+package schedule
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// dates returns the date of each occurrence
+func dates(occurrences []Occurrence) []string {
+	result := []string{}
+	for _, o := range occurrences {
+		result = append(result, o.Date)
+	}
+	return result
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    Rule
+		wantErr bool
+	}{
+		{rule: "FREQ=WEEKLY", want: Rule{Interval: 1}},
+		{
+			rule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH;UNTIL=20250601T000000Z",
+			want: Rule{Interval: 2, ByDay: []time.Weekday{time.Tuesday, time.Thursday}, Until: "2025-06-01"},
+		},
+		{rule: "freq=weekly;count=3;", want: Rule{Interval: 1, Count: 3}},
+		{rule: "", wantErr: true},
+		{rule: "INTERVAL=2", wantErr: true},
+		{rule: "FREQ=DAILY", wantErr: true},
+		{rule: "FREQ=WEEKLY;INTERVAL=0", wantErr: true},
+		{rule: "FREQ=WEEKLY;INTERVAL", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{rule: "FREQ=WEEKLY;UNTIL=2025", wantErr: true},
+		{rule: "FREQ=WEEKLY;COUNT=-1", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYMONTH=1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRule(tt.rule)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRule(%q) error = %v, want error %v", tt.rule, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRule(%q) = %+v, want %+v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestRuleString(t *testing.T) {
+	rules := []Rule{
+		{Interval: 1},
+		{Interval: 3, ByDay: []time.Weekday{time.Saturday, time.Monday}, Until: "2025-12-31"},
+		{Interval: 1, ByDay: []time.Weekday{time.Wednesday}, Count: 10},
+	}
+	for _, rule := range rules {
+		got, err := ParseRule(rule.String())
+		if err != nil {
+			t.Errorf("ParseRule(%q) error = %v", rule.String(), err)
+			continue
+		}
+		if !reflect.DeepEqual(got, rule) {
+			t.Errorf("ParseRule(%q) = %+v, want %+v", rule.String(), got, rule)
+		}
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	tue, thu := time.Tuesday, time.Thursday
+	start := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		rule       Rule
+		startDate  string
+		exceptions map[string]bool
+		from       time.Time
+		n          int
+		want       []string
+	}{
+		{
+			name: "defaults to the start date's weekday",
+			rule: Rule{Interval: 1},
+			from: start, n: 3,
+			want: []string{"2025-03-04", "2025-03-11", "2025-03-18"},
+		},
+		{
+			name: "interval and days",
+			rule: Rule{Interval: 2, ByDay: []time.Weekday{thu, tue}},
+			from: start, n: 4,
+			want: []string{"2025-03-04", "2025-03-06", "2025-03-18", "2025-03-20"},
+		},
+		{
+			name: "interval counts from the start date when from is later",
+			rule: Rule{Interval: 2},
+			from: time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC), n: 2,
+			want: []string{"2025-03-18", "2025-04-01"},
+		},
+		{
+			name: "count",
+			rule: Rule{Interval: 1, Count: 3},
+			from: start, n: 10,
+			want: []string{"2025-03-04", "2025-03-11", "2025-03-18"},
+		},
+		{
+			name:       "count includes cancelled dates",
+			rule:       Rule{Interval: 1, Count: 3},
+			exceptions: map[string]bool{"2025-03-11": true},
+			from:       start, n: 10,
+			want: []string{"2025-03-04", "2025-03-18"},
+		},
+		{
+			name: "count includes occurrences before from",
+			rule: Rule{Interval: 1, Count: 3},
+			from: time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC), n: 10,
+			want: []string{"2025-03-18"},
+		},
+		{
+			name: "until is inclusive",
+			rule: Rule{Interval: 1, Until: "2025-03-18"},
+			from: start, n: 10,
+			want: []string{"2025-03-04", "2025-03-11", "2025-03-18"},
+		},
+		{
+			name: "skips a session that already started today",
+			rule: Rule{Interval: 1},
+			from: time.Date(2025, 3, 4, 20, 0, 0, 0, time.UTC), n: 1,
+			want: []string{"2025-03-11"},
+		},
+		{
+			name:      "starts years before from",
+			rule:      Rule{Interval: 1},
+			startDate: "2019-01-01",
+			from:      time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), n: 2,
+			want: []string{"2025-03-04", "2025-03-11"},
+		},
+		{
+			name: "nothing before the start date",
+			rule: Rule{Interval: 1},
+			from: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), n: 1,
+			want: []string{"2025-03-04"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Schedule{
+				Rule:       tt.rule,
+				StartDate:  "2025-03-04",
+				StartTime:  "19:00",
+				Duration:   90 * time.Minute,
+				Location:   time.UTC,
+				Exceptions: tt.exceptions,
+			}
+			if tt.startDate != "" {
+				s.StartDate = tt.startDate
+			}
+			got, err := s.Occurrences(tt.from, tt.n)
+			if err != nil {
+				t.Fatalf("Occurrences() error = %v", err)
+			}
+			if !reflect.DeepEqual(dates(got), tt.want) {
+				t.Errorf("Occurrences() = %v, want %v", dates(got), tt.want)
+			}
+			for _, o := range got {
+				if o.EndsAt.Sub(o.StartsAt) != s.Duration {
+					t.Errorf("occurrence on %s lasts %v, want %v", o.Date, o.EndsAt.Sub(o.StartsAt), s.Duration)
+				}
+			}
+		})
+	}
+}
+
+func TestOccurrencesDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Schedule{Rule: Rule{Interval: 1}, StartDate: "2025-03-04", StartTime: "19:00", Duration: time.Hour, Location: loc}
+
+	got, err := s.Occurrences(time.Date(2025, 3, 1, 0, 0, 0, 0, loc), 2)
+	if err != nil {
+		t.Fatalf("Occurrences() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Occurrences() = %v, want 2 occurrences", dates(got))
+	}
+	for _, o := range got {
+		if local := o.StartsAt.In(loc); local.Hour() != 19 || local.Minute() != 0 {
+			t.Errorf("occurrence on %s starts at %s local, want 19:00", o.Date, local.Format("15:04"))
+		}
+	}
+	if gap := got[1].StartsAt.Sub(got[0].StartsAt); gap != 7*24*time.Hour-time.Hour {
+		t.Errorf("occurrences across the change are %v apart, want %v", gap, 7*24*time.Hour-time.Hour)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	s := Schedule{Rule: Rule{Interval: 1}, StartDate: "2025-03-04", StartTime: "19:00", Duration: time.Hour, Location: time.UTC}
+
+	got, err := s.Between(time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 18, 19, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Between() error = %v", err)
+	}
+	if want := []string{"2025-03-04", "2025-03-11"}; !reflect.DeepEqual(dates(got), want) {
+		t.Errorf("Between() = %v, want %v", dates(got), want)
+	}
+}
+
+func TestOccurrencesInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Schedule
+	}{
+		{name: "start date", s: Schedule{StartDate: "03/04/2025", StartTime: "19:00"}},
+		{name: "start time", s: Schedule{StartDate: "2025-03-04", StartTime: "7pm"}},
+		{name: "until date", s: Schedule{Rule: Rule{Until: "soon"}, StartDate: "2025-03-04", StartTime: "19:00"}},
+	}
+	for _, tt := range tests {
+		tt.s.Location = time.UTC
+		if _, err := tt.s.Occurrences(time.Now(), 1); err == nil {
+			t.Errorf("invalid %s: Occurrences() error = nil", tt.name)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package stats
+
+// Player is one player's appearance on a team
+type Player struct {
+	ID     uint
+	Goalie bool // Played goal in this game
+}
+
+// Team is one team's lineup and final score in a recorded game
+type Team struct {
+	Players []Player
+	Score   int
+}
+
+// PlayerStats is a player's record across a set of games. In games with more than two teams,
+// goals against is the average of the other teams' scores, and a win needs the top score
+// alone while sharing it is a tie.
+type PlayerStats struct {
+	PlayerID          uint         `json:"player_id"`
+	GamesPlayed       int          `json:"games_played"`
+	Wins              int          `json:"wins"`
+	Losses            int          `json:"losses"`
+	Ties              int          `json:"ties"`
+	GoalsFor          int          `json:"goals_for"`
+	GoalsAgainst      float64      `json:"goals_against"`
+	GoalDifferential  float64      `json:"goal_differential"` // Goals for minus goals against while on the team
+	GoalieAppearances int          `json:"goalie_appearances"`
+	Teammates         map[uint]int `json:"-"` // Games played on the same team, keyed by player ID
+}
+
+// Aggregate tallies each player's stats from recorded games
+func Aggregate(games [][]Team) map[uint]*PlayerStats {
+	result := make(map[uint]*PlayerStats)
+	get := func(id uint) *PlayerStats {
+		s, ok := result[id]
+		if !ok {
+			s = &PlayerStats{PlayerID: id, Teammates: make(map[uint]int)}
+			result[id] = s
+		}
+		return s
+	}
+
+	for _, teams := range games {
+		if len(teams) < 2 {
+			continue
+		}
+		for i, team := range teams {
+			against := 0.0
+			for j, other := range teams {
+				if j != i {
+					against += float64(other.Score)
+				}
+			}
+			against /= float64(len(teams) - 1)
+			won, tied := outcome(teams, i)
+
+			for _, p := range team.Players {
+				s := get(p.ID)
+				s.GamesPlayed++
+				s.GoalsFor += team.Score
+				s.GoalsAgainst += against
+				switch {
+				case won:
+					s.Wins++
+				case tied:
+					s.Ties++
+				default:
+					s.Losses++
+				}
+				if p.Goalie {
+					s.GoalieAppearances++
+				}
+				for _, mate := range team.Players {
+					if mate.ID != p.ID {
+						s.Teammates[mate.ID]++
+					}
+				}
+			}
+		}
+	}
+
+	for _, s := range result {
+		s.GoalDifferential = float64(s.GoalsFor) - s.GoalsAgainst
+	}
+	return result
+}
+
+// PairStats is how often two players have been on the same team and on opposing teams
+type PairStats struct {
+	Together     int     // Games on the same team
+	Apart        int     // Games on different teams
+	Recorded     int     // Games together with a recorded result
+	WinsTogether float64 // Recorded games together won, counting ties as half
+}
+
+// Pair is a key for two players, lowest ID first
+type Pair [2]uint
+
+// NewPair returns the key for two players in either order
+func NewPair(a, b uint) Pair {
+	if a > b {
+		a, b = b, a
+	}
+	return Pair{a, b}
+}
+
+// Pairs tallies every pair of players who have played in the same game. recorded says
+// which games have results, and only those count towards wins together.
+func Pairs(games [][]Team, recorded []bool) map[Pair]*PairStats {
+	result := make(map[Pair]*PairStats)
+	get := func(a, b uint) *PairStats {
+		key := NewPair(a, b)
+		s, ok := result[key]
+		if !ok {
+			s = &PairStats{}
+			result[key] = s
+		}
+		return s
+	}
+
+	for g, teams := range games {
+		for i, team := range teams {
+			won, tied := outcome(teams, i)
+			for x, p := range team.Players {
+				for _, mate := range team.Players[x+1:] {
+					s := get(p.ID, mate.ID)
+					s.Together++
+					if recorded[g] && len(teams) > 1 {
+						s.Recorded++
+						if won {
+							s.WinsTogether++
+						} else if tied {
+							s.WinsTogether += 0.5
+						}
+					}
+				}
+				for _, other := range teams[i+1:] {
+					for _, opponent := range other.Players {
+						get(p.ID, opponent.ID).Apart++
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// outcome reports whether team i won a game outright or tied for the top score
+func outcome(teams []Team, i int) (won, tied bool) {
+	best := 0
+	first := true
+	for j, other := range teams {
+		if j != i && (first || other.Score > best) {
+			best = other.Score
+			first = false
+		}
+	}
+	return teams[i].Score > best, teams[i].Score == best
+}
@@ -0,0 +1,136 @@
+package stats
+
+import "testing"
+
+// team builds a team from player IDs, the first marked as goalie when goalie is set
+func team(score int, goalie bool, ids ...uint) Team {
+	t := Team{Score: score}
+	for i, id := range ids {
+		t.Players = append(t.Players, Player{ID: id, Goalie: goalie && i == 0})
+	}
+	return t
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		name  string
+		games [][]Team
+		want  map[uint]PlayerStats
+	}{
+		{
+			name:  "win and loss",
+			games: [][]Team{{team(3, true, 1, 2), team(1, false, 3)}},
+			want: map[uint]PlayerStats{
+				1: {GamesPlayed: 1, Wins: 1, GoalsFor: 3, GoalsAgainst: 1, GoalDifferential: 2, GoalieAppearances: 1},
+				2: {GamesPlayed: 1, Wins: 1, GoalsFor: 3, GoalsAgainst: 1, GoalDifferential: 2},
+				3: {GamesPlayed: 1, Losses: 1, GoalsFor: 1, GoalsAgainst: 3, GoalDifferential: -2},
+			},
+		},
+		{
+			name:  "tie",
+			games: [][]Team{{team(2, false, 1), team(2, false, 2)}},
+			want: map[uint]PlayerStats{
+				1: {GamesPlayed: 1, Ties: 1, GoalsFor: 2, GoalsAgainst: 2},
+				2: {GamesPlayed: 1, Ties: 1, GoalsFor: 2, GoalsAgainst: 2},
+			},
+		},
+		{
+			name:  "three teams average goals against and share the top score as a tie",
+			games: [][]Team{{team(4, false, 1), team(4, false, 2), team(1, false, 3)}},
+			want: map[uint]PlayerStats{
+				1: {GamesPlayed: 1, Ties: 1, GoalsFor: 4, GoalsAgainst: 2.5, GoalDifferential: 1.5},
+				2: {GamesPlayed: 1, Ties: 1, GoalsFor: 4, GoalsAgainst: 2.5, GoalDifferential: 1.5},
+				3: {GamesPlayed: 1, Losses: 1, GoalsFor: 1, GoalsAgainst: 4, GoalDifferential: -3},
+			},
+		},
+		{
+			name: "games add up",
+			games: [][]Team{
+				{team(3, false, 1), team(1, false, 2)},
+				{team(0, false, 1), team(2, false, 2)},
+				{team(5, false, 1), team(5, false, 2)},
+			},
+			want: map[uint]PlayerStats{
+				1: {GamesPlayed: 3, Wins: 1, Losses: 1, Ties: 1, GoalsFor: 8, GoalsAgainst: 8},
+				2: {GamesPlayed: 3, Wins: 1, Losses: 1, Ties: 1, GoalsFor: 8, GoalsAgainst: 8},
+			},
+		},
+		{
+			name:  "games with one team are skipped",
+			games: [][]Team{{team(3, false, 1, 2)}},
+			want:  map[uint]PlayerStats{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Aggregate(tt.games)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Aggregate() has %d players, want %d", len(got), len(tt.want))
+			}
+			for id, want := range tt.want {
+				s, ok := got[id]
+				if !ok {
+					t.Fatalf("player %d missing", id)
+				}
+				g := *s
+				if g.GamesPlayed != want.GamesPlayed || g.Wins != want.Wins || g.Losses != want.Losses || g.Ties != want.Ties ||
+					g.GoalsFor != want.GoalsFor || g.GoalsAgainst != want.GoalsAgainst || g.GoalDifferential != want.GoalDifferential ||
+					g.GoalieAppearances != want.GoalieAppearances {
+					t.Errorf("player %d = %+v, want %+v", id, g, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAggregateTeammates(t *testing.T) {
+	got := Aggregate([][]Team{
+		{team(1, false, 1, 2, 3), team(0, false, 4)},
+		{team(1, false, 1, 2), team(0, false, 3, 4)},
+	})
+	tests := []struct {
+		player, mate uint
+		want         int
+	}{
+		{1, 2, 2},
+		{2, 1, 2},
+		{1, 3, 1},
+		{3, 4, 1},
+		{1, 4, 0},
+		{1, 1, 0},
+	}
+	for _, tt := range tests {
+		if n := got[tt.player].Teammates[tt.mate]; n != tt.want {
+			t.Errorf("player %d played with %d %d times, want %d", tt.player, tt.mate, n, tt.want)
+		}
+	}
+}
+
+func TestPairs(t *testing.T) {
+	games := [][]Team{
+		{team(3, false, 1, 2), team(1, false, 3)},
+		{team(2, false, 1, 3), team(2, false, 2)},
+		{team(0, false, 1, 2), team(0, false, 3)},
+	}
+	recorded := []bool{true, true, false}
+
+	tests := []struct {
+		a, b uint
+		want PairStats
+	}{
+		{a: 1, b: 2, want: PairStats{Together: 2, Apart: 1, Recorded: 1, WinsTogether: 1}},
+		{a: 2, b: 1, want: PairStats{Together: 2, Apart: 1, Recorded: 1, WinsTogether: 1}},
+		{a: 1, b: 3, want: PairStats{Together: 1, Apart: 2, Recorded: 1, WinsTogether: 0.5}},
+		{a: 2, b: 3, want: PairStats{Apart: 3}},
+	}
+	got := Pairs(games, recorded)
+	for _, tt := range tests {
+		s, ok := got[NewPair(tt.a, tt.b)]
+		if !ok {
+			t.Fatalf("pair %d-%d missing", tt.a, tt.b)
+		}
+		if *s != tt.want {
+			t.Errorf("pair %d-%d = %+v, want %+v", tt.a, tt.b, *s, tt.want)
+		}
+	}
+}
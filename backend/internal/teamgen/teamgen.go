@@ -2,6 +2,7 @@ package teamgen
 
 import (
 	"errors"
+	"math"
 	"math/rand"
 	"sort"
 	"time"
@@ -17,13 +18,31 @@ func init() {
 type Team struct {
 	Number      int             `json:"number"`
 	Players     []models.Player `json:"players"`
-	TotalWeight int             `json:"total_weight"`
+	TotalWeight float64         `json:"total_weight"`
 }
 
+// AlgorithmVersion identifies the balancing algorithm. Bump it when a change would generate
+// different teams from the same inputs.
+const AlgorithmVersion = 1
+
 // GenerateBalancedTeams creates balanced teams from a list of players
 // lockedPlayers is an array of player ID arrays - each inner array represents players that must be on the same team
 // separatedPlayers is an array of player ID arrays - each inner array represents players that must be on different teams
 func GenerateBalancedTeams(players []models.Player, numTeams int, lockedPlayers [][]uint, separatedPlayers [][]uint) ([]Team, error) {
+	return GenerateBalancedTeamsWithWeights(players, nil, numTeams, lockedPlayers, separatedPlayers)
+}
+
+// GenerateBalancedTeamsWithWeights works like GenerateBalancedTeams but balances on the
+// given effective weights (keyed by player ID). Players missing from weights fall back to
+// their SkillWeight.
+func GenerateBalancedTeamsWithWeights(players []models.Player, weights map[uint]float64, numTeams int, lockedPlayers [][]uint, separatedPlayers [][]uint) ([]Team, error) {
+	weightOf := func(p models.Player) float64 {
+		if w, ok := weights[p.ID]; ok {
+			return w
+		}
+		return p.SkillWeight
+	}
+
 	if numTeams < 2 {
 		return nil, errors.New("must have at least 2 teams")
 	}
@@ -68,7 +87,7 @@ func GenerateBalancedTeams(players []models.Player, numTeams int, lockedPlayers
 				}
 
 				teams[i].Players = append(teams[i].Players, player)
-				teams[i].TotalWeight += player.SkillWeight
+				teams[i].TotalWeight += weightOf(player)
 				assignedPlayers[playerID] = true
 			}
 		}
@@ -113,7 +132,7 @@ func GenerateBalancedTeams(players []models.Player, numTeams int, lockedPlayers
 
 				teamIdx := teamIndices[i]
 				teams[teamIdx].Players = append(teams[teamIdx].Players, player)
-				teams[teamIdx].TotalWeight += player.SkillWeight
+				teams[teamIdx].TotalWeight += weightOf(player)
 				assignedPlayers[playerID] = true
 			}
 		}
@@ -134,7 +153,7 @@ func GenerateBalancedTeams(players []models.Player, numTeams int, lockedPlayers
 
 	// Sort remaining players by skill weight (descending) for better balance
 	sort.Slice(remainingPlayers, func(i, j int) bool {
-		return remainingPlayers[i].SkillWeight > remainingPlayers[j].SkillWeight
+		return weightOf(remainingPlayers[i]) > weightOf(remainingPlayers[j])
 	})
 
 	// Assign remaining players using greedy algorithm (assign to team with lowest total weight)
@@ -159,7 +178,12 @@ func GenerateBalancedTeams(players []models.Player, numTeams int, lockedPlayers
 		minTeamIdx := minTeams[rand.Intn(len(minTeams))]
 
 		teams[minTeamIdx].Players = append(teams[minTeamIdx].Players, player)
-		teams[minTeamIdx].TotalWeight += player.SkillWeight
+		teams[minTeamIdx].TotalWeight += weightOf(player)
+	}
+
+	// Round totals so fractional weights don't leak floating point noise
+	for i := range teams {
+		teams[i].TotalWeight = math.Round(teams[i].TotalWeight*100) / 100
 	}
 
 	return teams, nil
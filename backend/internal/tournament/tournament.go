@@ -0,0 +1,411 @@
+package tournament
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/sticktoss/backend/internal/league"
+)
+
+// Brackets a match can belong to
+const (
+	BracketPool    = "pool"    // Round-robin pool play
+	BracketWinners = "winners" // Elimination bracket, or the winners' side of a double elimination
+	BracketLosers  = "losers"  // Losers' side of a double elimination
+	BracketFinal   = "final"   // Grand final of a double elimination
+	BracketReset   = "reset"   // Second grand final, played only if the losers' side champion wins the first
+)
+
+var (
+	// ErrLocked is returned when changing a result would change a match that has already been played
+	ErrLocked = errors.New("a later match depending on this result has already been played")
+	// ErrNotReady is returned when recording a match that doesn't have both teams yet or isn't played
+	ErrNotReady = errors.New("match is not ready to be played")
+)
+
+// Slot is one side of a match: 0 for home, 1 for away
+type Slot struct {
+	Match int // Index of the match
+	Side  int
+}
+
+// Match is a tournament match. Teams are identified by their seed, starting from 1.
+type Match struct {
+	Bracket  string
+	Pool     int // Pool number from 1 for pool matches
+	Round    int
+	Teams    [2]int  // Seeds of the home and away teams, 0 until decided
+	Byes     [2]bool // The side will never get a team, so the other advances without playing
+	Winner   int     // Seed of the winner, 0 until decided or if neither side had a team
+	Done     bool
+	Skipped  bool // Decided without being played, by a bye or a reset final that isn't needed
+	WinnerTo *Slot
+	LoserTo  *Slot
+}
+
+// Pools splits seeds 1 to numTeams into numPools pools, snaking so each pool gets a similar
+// spread of strong and weak teams
+func Pools(numTeams, numPools int) [][]int {
+	pools := make([][]int, numPools)
+	for i := 0; i < numTeams; i++ {
+		pool := i % numPools
+		if (i/numPools)%2 == 1 {
+			pool = numPools - 1 - pool
+		}
+		pools[pool] = append(pools[pool], i+1)
+	}
+	return pools
+}
+
+// PoolMatches returns a single round-robin within each pool
+func PoolMatches(pools [][]int) []Match {
+	var matches []Match
+	for p, seeds := range pools {
+		for r, round := range league.RoundRobin(len(seeds), 1) {
+			for _, pairing := range round {
+				matches = append(matches, Match{
+					Bracket: BracketPool,
+					Pool:    p + 1,
+					Round:   r + 1,
+					Teams:   [2]int{seeds[pairing.Home-1], seeds[pairing.Away-1]},
+				})
+			}
+		}
+	}
+	return matches
+}
+
+// PoolStanding is a team's record within its pool
+type PoolStanding struct {
+	Seed int `json:"seed"`
+	league.Standing
+}
+
+// PoolStandings ranks each pool's teams from their played pool matches. Teams level on
+// points, goal differential and goals scored are ranked by seed.
+func PoolStandings(pools [][]int, matches []Match, scores [][2]int, points league.Points) [][]PoolStanding {
+	result := make([][]PoolStanding, len(pools))
+	for p, seeds := range pools {
+		index := make(map[int]int, len(seeds))
+		for i, seed := range seeds {
+			index[seed] = i + 1
+		}
+
+		var results []league.Result
+		for i, m := range matches {
+			if m.Bracket != BracketPool || m.Pool != p+1 || !m.Done {
+				continue
+			}
+			results = append(results, league.Result{
+				Home:      index[m.Teams[0]],
+				Away:      index[m.Teams[1]],
+				HomeScore: scores[i][0],
+				AwayScore: scores[i][1],
+			})
+		}
+
+		for _, s := range league.Standings(len(seeds), results, points) {
+			result[p] = append(result[p], PoolStanding{Seed: seeds[s.Team-1], Standing: s})
+		}
+	}
+	return result
+}
+
+// Qualifiers returns the seeds of the top advance teams from each pool, in bracket seeding
+// order: every pool winner first, then every runner-up and so on, each place ordered by record
+func Qualifiers(standings [][]PoolStanding, advance int) []int {
+	var seeds []int
+	for place := 0; place < advance; place++ {
+		var tier []PoolStanding
+		for _, pool := range standings {
+			if place < len(pool) {
+				tier = append(tier, pool[place])
+			}
+		}
+		sort.SliceStable(tier, func(i, j int) bool {
+			a, b := tier[i], tier[j]
+			if a.Points != b.Points {
+				return a.Points > b.Points
+			}
+			if a.GoalDifferential != b.GoalDifferential {
+				return a.GoalDifferential > b.GoalDifferential
+			}
+			if a.GoalsFor != b.GoalsFor {
+				return a.GoalsFor > b.GoalsFor
+			}
+			return a.Seed < b.Seed
+		})
+		for _, s := range tier {
+			seeds = append(seeds, s.Seed)
+		}
+	}
+	return seeds
+}
+
+// SingleElimination returns a bracket for the given seeds, strongest first, in the standard
+// order where the top seeds can only meet late. Missing places are byes.
+func SingleElimination(seeds []int) []Match {
+	matches, _ := winnersBracket(seeds)
+	ResolveByes(matches)
+	return matches
+}
+
+// DoubleElimination returns a double elimination bracket for the given seeds, strongest
+// first. Losers drop into the losers' side, whose champion meets the winners' side champion
+// in a grand final, with a reset final if the losers' side champion wins it.
+func DoubleElimination(seeds []int) []Match {
+	matches, rounds := winnersBracket(seeds)
+	size := 1 << len(rounds)
+
+	// The losers' side alternates minor rounds between its own survivors and major rounds
+	// where they meet the losers dropping from the next winners' round
+	var prevMajor []int
+	for p := 1; p < len(rounds); p++ {
+		count := size >> (p + 1)
+
+		minor := make([]int, count)
+		for j := range minor {
+			minor[j] = len(matches)
+			matches = append(matches, Match{Bracket: BracketLosers, Round: 2*p - 1})
+			if p == 1 {
+				matches[rounds[0][2*j]].LoserTo = &Slot{Match: minor[j], Side: 0}
+				matches[rounds[0][2*j+1]].LoserTo = &Slot{Match: minor[j], Side: 1}
+			} else {
+				matches[prevMajor[2*j]].WinnerTo = &Slot{Match: minor[j], Side: 0}
+				matches[prevMajor[2*j+1]].WinnerTo = &Slot{Match: minor[j], Side: 1}
+			}
+		}
+
+		major := make([]int, count)
+		for j := range major {
+			major[j] = len(matches)
+			matches = append(matches, Match{Bracket: BracketLosers, Round: 2 * p})
+			matches[minor[j]].WinnerTo = &Slot{Match: major[j], Side: 0}
+			// Drop losers in reverse order so teams don't quickly meet again
+			matches[rounds[p][count-1-j]].LoserTo = &Slot{Match: major[j], Side: 1}
+		}
+		prevMajor = major
+	}
+
+	final := len(matches)
+	matches = append(matches, Match{Bracket: BracketFinal, Round: 1})
+	matches = append(matches, Match{Bracket: BracketReset, Round: 2})
+
+	winnersFinal := rounds[len(rounds)-1][0]
+	matches[winnersFinal].WinnerTo = &Slot{Match: final, Side: 0}
+	if len(prevMajor) > 0 {
+		matches[prevMajor[0]].WinnerTo = &Slot{Match: final, Side: 1}
+	} else {
+		// With two teams the winners' final loser goes straight to the grand final
+		matches[winnersFinal].LoserTo = &Slot{Match: final, Side: 1}
+	}
+
+	ResolveByes(matches)
+	return matches
+}
+
+// winnersBracket returns a single elimination bracket and the indexes of each round's matches
+func winnersBracket(seeds []int) ([]Match, [][]int) {
+	size := 2
+	for size < len(seeds) {
+		size *= 2
+	}
+	order := seedOrder(size)
+
+	var matches []Match
+	var rounds [][]int
+	for count, round := size/2, 1; count >= 1; count, round = count/2, round+1 {
+		indexes := make([]int, count)
+		for j := range indexes {
+			indexes[j] = len(matches)
+			m := Match{Bracket: BracketWinners, Round: round}
+			if round == 1 {
+				for side := 0; side < 2; side++ {
+					place := order[2*j+side]
+					if place <= len(seeds) {
+						m.Teams[side] = seeds[place-1]
+					} else {
+						m.Byes[side] = true
+					}
+				}
+			} else {
+				prev := rounds[round-2]
+				matches[prev[2*j]].WinnerTo = &Slot{Match: indexes[j], Side: 0}
+				matches[prev[2*j+1]].WinnerTo = &Slot{Match: indexes[j], Side: 1}
+			}
+			matches = append(matches, m)
+		}
+		rounds = append(rounds, indexes)
+	}
+	return matches, rounds
+}
+
+// seedOrder returns the bracket positions of seeds 1 to size, so that 1 and 2 can only meet
+// in the final
+func seedOrder(size int) []int {
+	order := []int{1}
+	for n := 2; n <= size; n *= 2 {
+		next := make([]int, 0, n)
+		for _, s := range order {
+			next = append(next, s, n+1-s)
+		}
+		order = next
+	}
+	return order
+}
+
+// Record sets the winning side of a played match and moves the winner and loser on to their
+// next matches. A correction replaces the earlier result, unless a match it fed has since
+// been played, in which case it returns ErrLocked.
+func Record(matches []Match, index, winnerSide int) error {
+	m := &matches[index]
+	if m.Skipped || m.Teams[0] == 0 || m.Teams[1] == 0 {
+		return ErrNotReady
+	}
+	if m.Done {
+		if err := checkUndo(matches, index); err != nil {
+			return err
+		}
+		clearResult(matches, index)
+	}
+
+	m.Done = true
+	m.Winner = m.Teams[winnerSide]
+	loser := m.Teams[1-winnerSide]
+
+	if m.Bracket == BracketFinal {
+		if reset := findBracket(matches, BracketReset); reset >= 0 {
+			if winnerSide == 0 {
+				// The winners' side champion is still unbeaten, so no reset is needed
+				matches[reset].Done = true
+				matches[reset].Skipped = true
+				matches[reset].Winner = m.Winner
+			} else {
+				matches[reset].Teams = m.Teams
+			}
+		}
+	}
+
+	place(matches, m.WinnerTo, m.Winner)
+	place(matches, m.LoserTo, loser)
+	ResolveByes(matches)
+	return nil
+}
+
+// checkUndo returns ErrLocked if clearing a match's result would change a played match.
+// Matches decided by a bye are cleared along with it.
+func checkUndo(matches []Match, index int) error {
+	m := matches[index]
+	for _, to := range fed(m) {
+		next := matches[to.Match]
+		if !next.Done {
+			continue
+		}
+		if next.Skipped && next.Bracket != BracketReset {
+			if err := checkUndo(matches, to.Match); err != nil {
+				return err
+			}
+			continue
+		}
+		return ErrLocked
+	}
+	if m.Bracket == BracketFinal {
+		if reset := findBracket(matches, BracketReset); reset >= 0 && matches[reset].Done && !matches[reset].Skipped {
+			return ErrLocked
+		}
+	}
+	return nil
+}
+
+// clearResult clears a match's result and takes its teams back out of later matches
+func clearResult(matches []Match, index int) {
+	m := &matches[index]
+	for _, to := range fed(*m) {
+		if matches[to.Match].Done {
+			clearResult(matches, to.Match)
+		}
+		matches[to.Match].Teams[to.Side] = 0
+	}
+	if m.Bracket == BracketFinal {
+		if reset := findBracket(matches, BracketReset); reset >= 0 {
+			matches[reset] = Match{Bracket: BracketReset, Round: matches[reset].Round}
+		}
+	}
+	m.Done = false
+	m.Skipped = false
+	m.Winner = 0
+}
+
+// fed returns the slots a decided match moved a team into
+func fed(m Match) []*Slot {
+	var slots []*Slot
+	if m.WinnerTo != nil && m.Winner != 0 {
+		slots = append(slots, m.WinnerTo)
+	}
+	if m.LoserTo != nil && m.Teams[0] != 0 && m.Teams[1] != 0 {
+		slots = append(slots, m.LoserTo)
+	}
+	return slots
+}
+
+// ResolveByes advances every team whose opponent is a bye, repeating until no more can
+func ResolveByes(matches []Match) {
+	for changed := true; changed; {
+		changed = false
+		for i := range matches {
+			m := &matches[i]
+			if m.Done || m.Bracket == BracketPool || m.Bracket == BracketReset {
+				continue
+			}
+			homeReady := m.Teams[0] > 0 || m.Byes[0]
+			awayReady := m.Teams[1] > 0 || m.Byes[1]
+			if !homeReady || !awayReady || !(m.Byes[0] || m.Byes[1]) {
+				continue
+			}
+
+			m.Done = true
+			m.Skipped = true
+			m.Winner = m.Teams[0] + m.Teams[1] // At most one side has a team
+			place(matches, m.WinnerTo, m.Winner)
+			place(matches, m.LoserTo, 0)
+			changed = true
+		}
+	}
+}
+
+// place puts a team into a slot, or marks it as a bye if there is no team
+func place(matches []Match, to *Slot, seed int) {
+	if to == nil {
+		return
+	}
+	if seed == 0 {
+		matches[to.Match].Byes[to.Side] = true
+	} else {
+		matches[to.Match].Teams[to.Side] = seed
+	}
+}
+
+// Champion returns the seed of the tournament winner, or 0 if it isn't decided yet
+func Champion(matches []Match) int {
+	if reset := findBracket(matches, BracketReset); reset >= 0 {
+		return matches[reset].Winner
+	}
+
+	// The single elimination final is the only bracket match not feeding another
+	for _, m := range matches {
+		if m.Bracket == BracketWinners && m.WinnerTo == nil {
+			return m.Winner
+		}
+	}
+	return 0
+}
+
+// findBracket returns the index of the first match in a bracket, or -1 if there is none
+func findBracket(matches []Match, bracket string) int {
+	for i, m := range matches {
+		if m.Bracket == bracket {
+			return i
+		}
+	}
+	return -1
+}
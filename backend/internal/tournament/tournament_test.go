@@ -0,0 +1,308 @@
+package tournament
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// seedsUpTo returns seeds 1 to n
+func seedsUpTo(n int) []int {
+	seeds := make([]int, n)
+	for i := range seeds {
+		seeds[i] = i + 1
+	}
+	return seeds
+}
+
+// playOut records every match as it becomes ready, picking winners with pick, and returns how
+// many matches each seed lost
+func playOut(t *testing.T, matches []Match, pick func(m Match) int) map[int]int {
+	t.Helper()
+	losses := make(map[int]int)
+	for played := true; played; {
+		played = false
+		for i, m := range matches {
+			if m.Done || m.Teams[0] == 0 || m.Teams[1] == 0 {
+				continue
+			}
+			side := pick(m)
+			if err := Record(matches, i, side); err != nil {
+				t.Fatalf("recording match %d: %v", i, err)
+			}
+			losses[m.Teams[1-side]]++
+			played = true
+		}
+	}
+	for i, m := range matches {
+		if !m.Done {
+			t.Fatalf("match %d (%s round %d) was never decided", i, m.Bracket, m.Round)
+		}
+	}
+	return losses
+}
+
+// Ways of picking the winning side of a match
+var pickers = map[string]func(seed int64) func(m Match) int{
+	"home": func(int64) func(Match) int { return func(Match) int { return 0 } },
+	"away": func(int64) func(Match) int { return func(Match) int { return 1 } },
+	"favourite": func(int64) func(Match) int {
+		return func(m Match) int {
+			if m.Teams[0] < m.Teams[1] {
+				return 0
+			}
+			return 1
+		}
+	},
+	"underdog": func(int64) func(Match) int {
+		return func(m Match) int {
+			if m.Teams[0] > m.Teams[1] {
+				return 0
+			}
+			return 1
+		}
+	},
+	"random": func(seed int64) func(Match) int {
+		r := rand.New(rand.NewSource(seed))
+		return func(Match) int { return r.Intn(2) }
+	},
+}
+
+func TestSingleEliminationLosses(t *testing.T) {
+	for n := 2; n <= 16; n++ {
+		for name, picker := range pickers {
+			matches := SingleElimination(seedsUpTo(n))
+			losses := playOut(t, matches, picker(int64(n)))
+
+			champion := Champion(matches)
+			if champion < 1 || champion > n {
+				t.Fatalf("%d teams, %s: champion = %d", n, name, champion)
+			}
+			for seed := 1; seed <= n; seed++ {
+				want := 1
+				if seed == champion {
+					want = 0
+				}
+				if losses[seed] != want {
+					t.Errorf("%d teams, %s: seed %d lost %d times, want %d", n, name, seed, losses[seed], want)
+				}
+			}
+		}
+	}
+}
+
+func TestDoubleEliminationLosses(t *testing.T) {
+	for n := 2; n <= 16; n++ {
+		for name, picker := range pickers {
+			matches := DoubleElimination(seedsUpTo(n))
+			losses := playOut(t, matches, picker(int64(n)))
+
+			champion := Champion(matches)
+			if champion < 1 || champion > n {
+				t.Fatalf("%d teams, %s: champion = %d", n, name, champion)
+			}
+			for seed := 1; seed <= n; seed++ {
+				if seed == champion {
+					if losses[seed] > 1 {
+						t.Errorf("%d teams, %s: champion %d lost %d times", n, name, seed, losses[seed])
+					}
+					continue
+				}
+				if losses[seed] != 2 {
+					t.Errorf("%d teams, %s: seed %d lost %d times, want 2", n, name, seed, losses[seed])
+				}
+			}
+		}
+	}
+}
+
+func TestSingleEliminationByes(t *testing.T) {
+	tests := []struct {
+		teams int
+		byes  []int // Seeds that skip the first round
+	}{
+		{teams: 2, byes: nil},
+		{teams: 3, byes: []int{1}},
+		{teams: 5, byes: []int{1, 2, 3}},
+		{teams: 6, byes: []int{1, 2}},
+		{teams: 8, byes: nil},
+	}
+	for _, tt := range tests {
+		matches := SingleElimination(seedsUpTo(tt.teams))
+		skipped := make(map[int]bool)
+		for _, m := range matches {
+			if m.Round == 1 && m.Skipped {
+				skipped[m.Winner] = true
+			}
+		}
+		if len(skipped) != len(tt.byes) {
+			t.Errorf("%d teams: byes for %v, want %v", tt.teams, skipped, tt.byes)
+			continue
+		}
+		for _, seed := range tt.byes {
+			if !skipped[seed] {
+				t.Errorf("%d teams: seed %d has no bye", tt.teams, seed)
+			}
+		}
+	}
+}
+
+func TestRecordCorrections(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   func() []Match
+		play    []int // Indexes of matches to record, home winning, before the correction
+		correct int   // Match whose result is changed to an away win
+		wantErr error
+	}{
+		{
+			name:    "correction before the next match",
+			build:   func() []Match { return SingleElimination(seedsUpTo(4)) },
+			play:    []int{0, 1},
+			correct: 0,
+		},
+		{
+			name:    "correction after the next match",
+			build:   func() []Match { return SingleElimination(seedsUpTo(4)) },
+			play:    []int{0, 1, 2},
+			correct: 0,
+			wantErr: ErrLocked,
+		},
+		{
+			name:    "correction before meeting a team that had a bye",
+			build:   func() []Match { return SingleElimination(seedsUpTo(6)) },
+			play:    []int{1},
+			correct: 1,
+		},
+		{
+			name:    "loser already played on the losers' side",
+			build:   func() []Match { return DoubleElimination(seedsUpTo(4)) },
+			play:    []int{0, 1, 3},
+			correct: 0,
+			wantErr: ErrLocked,
+		},
+		{
+			name:    "unplayed match",
+			build:   func() []Match { return SingleElimination(seedsUpTo(4)) },
+			play:    nil,
+			correct: 2,
+			wantErr: ErrNotReady,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := tt.build()
+			for _, i := range tt.play {
+				if err := Record(matches, i, 0); err != nil {
+					t.Fatalf("recording match %d: %v", i, err)
+				}
+			}
+			before := append([]Match(nil), matches...)
+
+			err := Record(matches, tt.correct, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Record() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				for i := range matches {
+					if matches[i].Teams != before[i].Teams || matches[i].Winner != before[i].Winner || matches[i].Done != before[i].Done {
+						t.Errorf("match %d changed by a rejected correction", i)
+					}
+				}
+				return
+			}
+
+			m := matches[tt.correct]
+			if m.Winner != m.Teams[1] {
+				t.Errorf("winner = %d, want %d", m.Winner, m.Teams[1])
+			}
+			if to := m.WinnerTo; to != nil && matches[to.Match].Teams[to.Side] != m.Winner {
+				t.Errorf("next match has seed %d, want %d", matches[to.Match].Teams[to.Side], m.Winner)
+			}
+		})
+	}
+}
+
+func TestGrandFinalReset(t *testing.T) {
+	tests := []struct {
+		name      string
+		finalSide int
+		wantReset bool
+	}{
+		{name: "winners' side champion wins", finalSide: 0, wantReset: false},
+		{name: "losers' side champion wins", finalSide: 1, wantReset: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := DoubleElimination(seedsUpTo(4))
+			final := findBracket(matches, BracketFinal)
+			reset := findBracket(matches, BracketReset)
+
+			// Play everything before the grand final with the home side winning
+			for played := true; played; {
+				played = false
+				for i, m := range matches {
+					if i == final || m.Done || m.Teams[0] == 0 || m.Teams[1] == 0 {
+						continue
+					}
+					if err := Record(matches, i, 0); err != nil {
+						t.Fatal(err)
+					}
+					played = true
+				}
+			}
+
+			if err := Record(matches, final, tt.finalSide); err != nil {
+				t.Fatal(err)
+			}
+			if got := !matches[reset].Skipped; got != tt.wantReset {
+				t.Fatalf("reset needed = %v, want %v", got, tt.wantReset)
+			}
+			if !tt.wantReset {
+				if Champion(matches) != matches[final].Teams[0] {
+					t.Errorf("champion = %d, want %d", Champion(matches), matches[final].Teams[0])
+				}
+				return
+			}
+
+			if Champion(matches) != 0 {
+				t.Errorf("champion decided before the reset final")
+			}
+			if err := Record(matches, reset, 0); err != nil {
+				t.Fatal(err)
+			}
+			if err := Record(matches, final, 0); !errors.Is(err, ErrLocked) {
+				t.Errorf("correcting the final after the reset: error = %v, want ErrLocked", err)
+			}
+		})
+	}
+}
+
+func TestPools(t *testing.T) {
+	tests := []struct {
+		teams, pools int
+		want         [][]int
+	}{
+		{teams: 4, pools: 2, want: [][]int{{1, 4}, {2, 3}}},
+		{teams: 6, pools: 2, want: [][]int{{1, 4, 5}, {2, 3, 6}}},
+		{teams: 7, pools: 3, want: [][]int{{1, 6, 7}, {2, 5}, {3, 4}}},
+	}
+	for _, tt := range tests {
+		got := Pools(tt.teams, tt.pools)
+		if len(got) != len(tt.want) {
+			t.Fatalf("Pools(%d, %d) = %v, want %v", tt.teams, tt.pools, got, tt.want)
+		}
+		for p := range got {
+			if len(got[p]) != len(tt.want[p]) {
+				t.Errorf("Pools(%d, %d) = %v, want %v", tt.teams, tt.pools, got, tt.want)
+				break
+			}
+			for i := range got[p] {
+				if got[p][i] != tt.want[p][i] {
+					t.Errorf("Pools(%d, %d) = %v, want %v", tt.teams, tt.pools, got, tt.want)
+					break
+				}
+			}
+		}
+	}
+}
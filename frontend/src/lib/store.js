@@ -27,3 +27,13 @@ export const skillLevels = {
     description: "Effortless, powerful skating with elite edge work and explosive speed. Stickhandles in a phone booth and protects the puck naturally. Makes high-difficulty passes look routine and sees plays developing before they happen. Can pick corners consistently and has a legitimately hard, accurate shot. Reads the game at a different speed than everyone else—always in the right position. Almost certainly played high school hockey, at least. Makes everyone else look slow. The guy who \"takes it easy\" and still dominates."
   }
 };
+
+// The label and description of a weight on the built-in 1-5 scale, empty for weights off it.
+// Custom skill scales are only supported through the API.
+export function skillLabel(weight) {
+  return skillLevels[weight]?.label ?? '';
+}
+
+export function skillDescription(weight) {
+  return skillLevels[weight]?.description ?? '';
+}